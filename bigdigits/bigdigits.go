@@ -0,0 +1,143 @@
+package bigdigits
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Font is a set of equally tall glyphs for the characters of a clock, ie the
+// digits and the colon.
+type Font struct {
+	Name   string
+	glyphs map[rune][]string
+}
+
+var fonts = map[string]Font{
+	"block": {
+		Name: "block",
+		glyphs: map[rune][]string{
+			'0': {" ███ ", "█   █", "█   █", "█   █", " ███ "},
+			'1': {"  █  ", " ██  ", "  █  ", "  █  ", " ███ "},
+			'2': {"████ ", "    █", " ███ ", "█    ", "█████"},
+			'3': {"████ ", "    █", " ███ ", "    █", "████ "},
+			'4': {"█   █", "█   █", "█████", "    █", "    █"},
+			'5': {"█████", "█    ", "████ ", "    █", "████ "},
+			'6': {" ███ ", "█    ", "████ ", "█   █", " ███ "},
+			'7': {"█████", "    █", "   █ ", "  █  ", "  █  "},
+			'8': {" ███ ", "█   █", " ███ ", "█   █", " ███ "},
+			'9': {" ███ ", "█   █", " ████", "    █", " ███ "},
+			':': {" ", "█", " ", "█", " "},
+		},
+	},
+	"slim": {
+		Name: "slim",
+		glyphs: map[rune][]string{
+			'0': {"┌─┐", "│ │", "└─┘"},
+			'1': {"  ╷", "  │", "  ╵"},
+			'2': {"╶─┐", "┌─┘", "└─╴"},
+			'3': {"╶─┐", " ─┤", "╶─┘"},
+			'4': {"╷ ╷", "└─┤", "  ╵"},
+			'5': {"┌─╴", "└─┐", "╶─┘"},
+			'6': {"┌─╴", "├─┐", "└─┘"},
+			'7': {"╶─┐", "  │", "  ╵"},
+			'8': {"┌─┐", "├─┤", "└─┘"},
+			'9': {"┌─┐", "└─┤", "╶─┘"},
+			':': {" ", ":", " "},
+		},
+	},
+	"ascii": {
+		Name: "ascii",
+		glyphs: map[rune][]string{
+			'0': {"###", "# #", "# #", "# #", "###"},
+			'1': {" # ", "## ", " # ", " # ", "###"},
+			'2': {"###", "  #", "###", "#  ", "###"},
+			'3': {"###", "  #", "###", "  #", "###"},
+			'4': {"# #", "# #", "###", "  #", "  #"},
+			'5': {"###", "#  ", "###", "  #", "###"},
+			'6': {"###", "#  ", "###", "# #", "###"},
+			'7': {"###", "  #", "  #", "  #", "  #"},
+			'8': {"###", "# #", "###", "# #", "###"},
+			'9': {"###", "# #", "###", "  #", "###"},
+			':': {" ", "#", " ", "#", " "},
+		},
+	},
+}
+
+// maxScale caps how far Fit will blow the digits up on very large windows.
+const maxScale = 4
+
+func Lookup(name string) (Font, bool) {
+	f, ok := fonts[name]
+	return f, ok
+}
+
+// Names lists the built-in fonts.
+func Names() []string {
+	names := []string{}
+	for name := range fonts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (f Font) height() int {
+	return len(f.glyphs['0'])
+}
+
+// Size returns the width and height of s rendered at the given scale.
+func (f Font) Size(s string, scale int) (int, int) {
+	width := 0
+	for _, r := range s {
+		if width > 0 {
+			width += scale
+		}
+		width += utf8.RuneCountInString(f.glyph(r)[0]) * scale
+	}
+	return width, f.height() * scale
+}
+
+// Render draws s in the font, with every cell repeated scale times in both
+// directions. Characters the font doesn't have are drawn as blanks.
+func (f Font) Render(s string, scale int) string {
+	lines := make([]string, f.height()*scale)
+	for row := 0; row < f.height(); row++ {
+		var b strings.Builder
+		for i, r := range s {
+			if i > 0 {
+				b.WriteString(strings.Repeat(" ", scale))
+			}
+			for _, c := range f.glyph(r)[row] {
+				b.WriteString(strings.Repeat(string(c), scale))
+			}
+		}
+		for i := 0; i < scale; i++ {
+			lines[row*scale+i] = b.String()
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Fit renders s at the largest scale that fits within width and height. It
+// returns false if s doesn't fit even at the smallest scale.
+func (f Font) Fit(s string, width int, height int) (string, bool) {
+	for scale := maxScale; scale > 0; scale-- {
+		w, h := f.Size(s, scale)
+		if w <= width && h <= height {
+			return f.Render(s, scale), true
+		}
+	}
+	return "", false
+}
+
+func (f Font) glyph(r rune) []string {
+	if g, ok := f.glyphs[r]; ok {
+		return g
+	}
+	blank := make([]string, f.height())
+	for i := range blank {
+		blank[i] = strings.Repeat(" ", utf8.RuneCountInString(f.glyphs['0'][i]))
+	}
+	return blank
+}
@@ -0,0 +1,52 @@
+package bigdigits
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBigDigits(t *testing.T) {
+	Convey("Font", t, func() {
+		f, ok := Lookup("ascii")
+		So(ok, ShouldBeTrue)
+
+		Convey("Renders glyphs side by side", func() {
+			So(f.Render("1:0", 1), ShouldEqual, strings.Join([]string{
+				" #    ###",
+				"##  # # #",
+				" #    # #",
+				" #  # # #",
+				"###   ###",
+			}, "\n"))
+		})
+
+		Convey("Scales in both directions", func() {
+			w, h := f.Size("12", 2)
+			So(w, ShouldEqual, 14)
+			So(h, ShouldEqual, 10)
+			So(strings.Split(f.Render("1", 2), "\n")[0], ShouldEqual, "  ##  ")
+		})
+
+		Convey("Fit picks the largest scale that fits", func() {
+			s, ok := f.Fit("00:00", 40, 12)
+			So(ok, ShouldBeTrue)
+			So(len(strings.Split(s, "\n")), ShouldEqual, 10)
+		})
+
+		Convey("Fit fails when there is no room", func() {
+			_, ok := f.Fit("00:00", 10, 3)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("Every font has every clock glyph", func() {
+			for _, name := range Names() {
+				f, _ := Lookup(name)
+				for _, r := range "0123456789:" {
+					So(len(f.glyphs[r]), ShouldEqual, f.height())
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/guysherman/tomato/config"
+	"github.com/guysherman/tomato/duration"
+	"github.com/guysherman/tomato/export"
+	"github.com/guysherman/tomato/history"
+	"github.com/guysherman/tomato/report"
+)
+
+// runExport writes the recorded history, between the dates given, to stdout
+// or a file.
+func runExport(args []string, stdout io.Writer, stderr io.Writer) int {
+	var format, since, until, output, configPath string
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	flags.StringVar(&format, "format", "csv", "Sets the format, one of csv, json or ics")
+	flags.StringVar(&since, "since", "", "Exports periods from this date, as yyyy-mm-dd")
+	flags.StringVar(&until, "until", "", "Exports periods up to and including this date, as yyyy-mm-dd")
+	flags.StringVar(&output, "o", "", "Sets the file to write to (default stdout)")
+	flags.StringVar(&configPath, "c", "", "Sets the path of the config file (default <user config dir>/tomato/config.json)")
+	flags.Parse(args)
+
+	if !export.Format(format).Valid() {
+		fmt.Fprintf(stderr, "Error reading -format: unknown format %q, expected csv, json or ics\n", format)
+		return exitError
+	}
+	from, to, err := parseRange(since, until)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error", err)
+		return exitError
+	}
+
+	records, _, err := loadHistory(configPath, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error", err)
+		return exitError
+	}
+
+	err = writeOutput(output, stdout, func(w io.Writer) error {
+		return export.Write(w, export.Format(format), history.Between(records, from, to))
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, "Error writing export:", err)
+		return exitError
+	}
+	return 0
+}
+
+// runReport prints a timesheet of the hours worked each day of a week, per
+// project, task or tag, rounded for billing.
+func runReport(args []string, stdout io.Writer, stderr io.Writer) int {
+	var by, round, rounding, minimum, week, format, output, configPath string
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	flags.StringVar(&by, "by", "project", "Sets the rows, one of project, task or tag")
+	flags.StringVar(&round, "round", "", "Rounds each day's time to a multiple of this, eg 15m (default from the config)")
+	flags.StringVar(&rounding, "rounding", "", "Sets the direction to round in, one of up, nearest or down (default from the config)")
+	flags.StringVar(&minimum, "min", "", "Sets the least time billed for a day with any time on it, eg 30m (default from the config)")
+	flags.StringVar(&week, "week", "", "Sets the week, as yyyy-Www eg 2026-W42 (default this week)")
+	flags.StringVar(&format, "format", "table", "Sets the format, one of table or csv")
+	flags.StringVar(&output, "o", "", "Sets the file to write to (default stdout)")
+	flags.StringVar(&configPath, "c", "", "Sets the path of the config file (default <user config dir>/tomato/config.json)")
+	flags.Parse(args)
+
+	if !report.Grouping(by).Valid() {
+		fmt.Fprintf(stderr, "Error reading -by: unknown grouping %q, expected project, task or tag\n", by)
+		return exitError
+	}
+	if format != "table" && format != "csv" {
+		fmt.Fprintf(stderr, "Error reading -format: unknown format %q, expected table or csv\n", format)
+		return exitError
+	}
+	start := history.StartOfWeek(time.Now())
+	if week != "" {
+		var err error
+		if start, err = report.ParseWeek(week, time.Local); err != nil {
+			fmt.Fprintln(stderr, "Error reading -week:", err)
+			return exitError
+		}
+	}
+
+	records, cfg, err := loadHistory(configPath, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error", err)
+		return exitError
+	}
+	rules, err := reportRules(cfg.Report, round, rounding, minimum)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error", err)
+		return exitError
+	}
+
+	r := report.Build(records, report.Grouping(by), rules, start, 7)
+	write := r.WriteTable
+	if format == "csv" {
+		write = r.WriteCSV
+	}
+	if err := writeOutput(output, stdout, write); err != nil {
+		fmt.Fprintln(stderr, "Error writing report:", err)
+		return exitError
+	}
+	return 0
+}
+
+// writeOutput writes to the file named by -o, or to stdout if there isn't one.
+// The file is closed before returning, as that's when a write can fail.
+func writeOutput(output string, stdout io.Writer, write func(io.Writer) error) error {
+	if output == "" {
+		return write(stdout)
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// reportRules reads the billing rules from the config, overridden by any
+// flags that were given.
+func reportRules(cfg config.Report, round string, rounding string, minimum string) (report.Rules, error) {
+	rules := report.Rules{Rounding: report.Rounding(cfg.Rounding)}
+	if rounding != "" {
+		rules.Rounding = report.Rounding(rounding)
+	}
+	if rules.Rounding == "" {
+		rules.Rounding = report.Up
+	}
+	if !rules.Rounding.Valid() {
+		source := "report.rounding in the config file"
+		if rounding != "" {
+			source = "-rounding"
+		}
+		return rules, fmt.Errorf("reading %s: unknown direction %q, expected up, nearest or down", source, rules.Rounding)
+	}
+
+	var err error
+	if rules.Round, err = reportDuration("-round", "report.round", round, cfg.Round); err != nil {
+		return rules, err
+	}
+	if rules.Minimum, err = reportDuration("-min", "report.minimum", minimum, cfg.Minimum); err != nil {
+		return rules, err
+	}
+	return rules, nil
+}
+
+// reportDuration reads the flag if it was given, or else the config key. An
+// empty value is zero, and a flag of 0 turns off a rule set in the config.
+func reportDuration(flagName string, key string, flagValue string, configValue string) (time.Duration, error) {
+	if flagValue != "" {
+		d, err := duration.ParseOptional(flagValue)
+		if err != nil {
+			return 0, fmt.Errorf("reading %s: %w", flagName, err)
+		}
+		return d, nil
+	}
+	if configValue != "" {
+		d, err := duration.ParseOptional(configValue)
+		if err != nil {
+			return 0, fmt.Errorf("reading %s in the config file: %w", key, err)
+		}
+		return d, nil
+	}
+	return 0, nil
+}
+
+// runStatus prints the progress towards the goals, and the current streak.
+// With -short it's a single line, for a status bar or a shell prompt.
+func runStatus(args []string, stdout io.Writer, stderr io.Writer) int {
+	var configPath string
+	var short bool
+	flags := flag.NewFlagSet("status", flag.ExitOnError)
+	flags.BoolVar(&short, "short", false, "Prints a single line")
+	flags.StringVar(&configPath, "c", "", "Sets the path of the config file (default <user config dir>/tomato/config.json)")
+	flags.Parse(args)
+
+	records, cfg, err := loadHistory(configPath, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error", err)
+		return exitError
+	}
+	targets, err := newGoals(cfg.Goals)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error loading goals:", err)
+		return exitError
+	}
+
+	p := targets.Measure(records, time.Now())
+	if short {
+		// Without goals or a streak there's no summary, but today's count is
+		// still worth showing.
+		summary := targets.Summary(p)
+		if summary == "" {
+			summary = fmt.Sprintf("%d 🍅 today", p.Today)
+		}
+		fmt.Fprintln(stdout, summary)
+		return 0
+	}
+
+	today := fmt.Sprintf("%d 🍅", p.Today)
+	if targets.Daily > 0 {
+		today = fmt.Sprintf("%d of %d 🍅", p.Today, targets.Daily)
+	}
+	week := fmt.Sprintf("%s focused", duration.FormatHours(p.Week))
+	if targets.Weekly > 0 {
+		week = fmt.Sprintf("%s of %s focused", duration.FormatHours(p.Week), duration.FormatHours(targets.Weekly))
+	}
+	fmt.Fprintf(stdout, "%-10s %s\n", "Today", today)
+	fmt.Fprintf(stdout, "%-10s %s\n", "This week", week)
+	fmt.Fprintf(stdout, "%-10s %s, longest %s\n", "Streak", days(p.Streak), days(p.Longest))
+	return 0
+}
+
+func days(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// parseRange reads the dates given with -since and -until. Either can be left
+// out, to leave that end of the range open. until is inclusive, so the range
+// runs to the start of the following day.
+func parseRange(since string, until string) (time.Time, time.Time, error) {
+	from := time.Time{}
+	to := time.Date(9999, 1, 1, 0, 0, 0, 0, time.Local)
+
+	if since != "" {
+		d, err := time.ParseInLocation("2006-01-02", since, time.Local)
+		if err != nil {
+			return from, to, fmt.Errorf("reading -since: expected yyyy-mm-dd, not %q", since)
+		}
+		from = d
+	}
+	if until != "" {
+		d, err := time.ParseInLocation("2006-01-02", until, time.Local)
+		if err != nil {
+			return from, to, fmt.Errorf("reading -until: expected yyyy-mm-dd, not %q", until)
+		}
+		to = d.AddDate(0, 0, 1)
+	}
+	return from, to, nil
+}
+
+// loadHistory reads the config, and the history kept in its data dir, for
+// the commands that work on history rather than running the timer. Lines of
+// the history that can't be read are warned about on stderr, and left out.
+func loadHistory(configPath string, stderr io.Writer) ([]history.Record, config.Config, error) {
+	cfg, err := loadConfig(resolveConfigPath(configPath))
+	if err != nil {
+		return nil, cfg, fmt.Errorf("loading config: %w", err)
+	}
+	dataDir, err := resolveDataDir(cfg)
+	if err != nil {
+		return nil, cfg, fmt.Errorf("finding data dir: %w", err)
+	}
+	records, err := historyStore(dataDir).Load()
+	var skipped *history.SkippedError
+	if errors.As(err, &skipped) {
+		fmt.Fprintf(stderr, "Warning: %v\n", err)
+	} else if err != nil {
+		return nil, cfg, fmt.Errorf("loading history: %w", err)
+	}
+	return records, cfg, nil
+}
+
+func historyStore(dataDir string) history.Store {
+	return history.Store{Path: filepath.Join(dataDir, "history.jsonl")}
+}
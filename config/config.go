@@ -0,0 +1,332 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/guysherman/tomato/theme"
+)
+
+// MessageTemplate holds the notification title and body templates for a phase.
+// Both are text/template strings, see notifications.Vars for the available fields.
+type MessageTemplate struct {
+	Title        string `json:"title"`
+	Body         string `json:"body"`
+	MessagesFile string `json:"messages_file,omitempty"`
+}
+
+type Notifications struct {
+	Backend    string          `json:"backend"`
+	Focus      MessageTemplate `json:"focus"`
+	ShortBreak MessageTemplate `json:"short_break"`
+	LongBreak  MessageTemplate `json:"long_break"`
+	Goal       MessageTemplate `json:"goal"`
+	Budget     MessageTemplate `json:"budget"`
+}
+
+// Warning is an advance notification sent when Before is left in a period.
+// Phase is one of focus, short_break, long_break or break (both kinds of break),
+// and an empty Phase applies to every period.
+type Warning struct {
+	Phase  string `json:"phase,omitempty"`
+	Before string `json:"before"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	Hook   string `json:"hook,omitempty"`
+}
+
+// Terminal controls reporting the timer in the terminal's window title and in
+// ConEmu/Windows Terminal style taskbar progress.
+type Terminal struct {
+	Title    bool `json:"title"`
+	Progress bool `json:"progress"`
+}
+
+// Display controls how the timer is drawn. Font names one of the big digit
+// fonts, or "small" to draw the time left as plain text.
+type Display struct {
+	Font string `json:"font"`
+}
+
+// Schedule holds the times of day, as hh:mm, that the forecast counts tomatoes
+// up to. The forecast uses whichever of DayEnd and Boundaries comes next.
+// DayStart and DayEnd are the working hours that the day's plan has to fit in.
+type Schedule struct {
+	DayStart   string   `json:"day_start"`
+	DayEnd     string   `json:"day_end"`
+	Boundaries []string `json:"boundaries,omitempty"`
+}
+
+// Timer holds the lengths of the periods and the scripts run around them. The
+// command line flags take precedence over these.
+type Timer struct {
+	Focus            string `json:"focus"`
+	ShortBreak       string `json:"short_break"`
+	LongBreak        string `json:"long_break"`
+	LongBreakTomatos int    `json:"long_break_tomatos"`
+	QuietModeScript  string `json:"quiet_mode_script"`
+	NoiseModeScript  string `json:"noise_mode_script"`
+}
+
+// Journal appends completed focus periods to daily notes. Format is org or
+// markdown, and an empty Format turns the journal off. Path is a text/template
+// executed with the time the period started, eg
+// "~/notes/{{.Format \"2006-01-02\"}}.md".
+type Journal struct {
+	Format string `json:"format,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+// Goals are the targets shown on the timer and by tomato status. Zero turns a
+// goal off.
+type Goals struct {
+	DailyTomatos int     `json:"daily_tomatos,omitempty"`
+	WeeklyHours  float64 `json:"weekly_hours,omitempty"`
+}
+
+// Project holds the settings for a project or client. WeeklyHours is the
+// most time to spend on it each week, or zero for no budget.
+type Project struct {
+	WeeklyHours float64 `json:"weekly_hours,omitempty"`
+}
+
+// Report holds the billing rules for tomato report. Round and Minimum are
+// durations, and Rounding is up, nearest or down. The command line flags take
+// precedence over these.
+type Report struct {
+	Round    string `json:"round,omitempty"`
+	Rounding string `json:"rounding"`
+	Minimum  string `json:"minimum,omitempty"`
+}
+
+// Config is the contents of the config file. Reflection asks for a note and
+// a rating after each focus period. Theme names either a built-in
+// theme or one defined in Themes. Keys rebinds actions in the timer view, see
+// timerview.Actions. DataDir is where history and tasks are kept, and defaults
+// to DefaultDataDir.
+type Config struct {
+	Timer         Timer                  `json:"timer"`
+	Notifications Notifications          `json:"notifications"`
+	Warnings      []Warning              `json:"warnings,omitempty"`
+	Terminal      Terminal               `json:"terminal"`
+	Display       Display                `json:"display"`
+	Schedule      Schedule               `json:"schedule"`
+	Theme         string                 `json:"theme"`
+	Themes        map[string]theme.Theme `json:"themes,omitempty"`
+	Keys          map[string][]string    `json:"keys,omitempty"`
+	DataDir       string                 `json:"data_dir,omitempty"`
+	Journal       Journal                `json:"journal"`
+	Reflection    bool                   `json:"reflection"`
+	Goals         Goals                  `json:"goals"`
+	Projects      map[string]Project     `json:"projects,omitempty"`
+	Report        Report                 `json:"report"`
+}
+
+func Default() Config {
+	breakMessage := MessageTemplate{
+		Title: "Break Complete!",
+		Body:  "Hey you! Time to knuckle down.",
+	}
+
+	return Config{
+		Timer: Timer{
+			Focus:            "25m",
+			ShortBreak:       "5m",
+			LongBreak:        "15m",
+			LongBreakTomatos: 4,
+			QuietModeScript:  "tomato_quiet.sh",
+			NoiseModeScript:  "tomato_noise.sh",
+		},
+		Notifications: Notifications{
+			Backend: "kitty",
+			Focus: MessageTemplate{
+				Title: "Tomato Complete!",
+				Body:  "Well done! Another tomato down.",
+			},
+			ShortBreak: breakMessage,
+			LongBreak:  breakMessage,
+			Goal: MessageTemplate{
+				Title: "Daily goal reached!",
+				Body:  "That's {{.Today}} tomatos today, nice work.",
+			},
+			Budget: MessageTemplate{
+				Title: "Over budget",
+				Body:  "That's {{.Spent}} of {{.Budget}} on {{.Project}} this week.",
+			},
+		},
+		Terminal: Terminal{
+			Title: true,
+		},
+		Display: Display{
+			Font: "block",
+		},
+		Schedule: Schedule{
+			DayStart: "09:00",
+			DayEnd:   "17:00",
+		},
+		Theme: "default",
+		Report: Report{
+			Rounding: "up",
+		},
+	}
+}
+
+// DefaultPath returns the location of the config file, which is tomato/config.json
+// inside the user's config directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tomato", "config.json"), nil
+}
+
+// DefaultDataDir is the directory history and tasks are kept in, following
+// the XDG base directory spec: $XDG_DATA_HOME/tomato, or ~/.local/share/tomato.
+func DefaultDataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "tomato"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "tomato"), nil
+}
+
+// Load reads the config file at path on top of the defaults. A missing file is
+// not an error, it just yields the defaults.
+func Load(path string) (Config, error) {
+	c := Default()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	} else if err != nil {
+		return c, err
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// Set changes a single setting in the config file at path, creating the file
+// and its directory if needed. Key is the setting's path through the file, eg
+// "timer.focus". Only that value is written: the rest of the file is left as
+// the user wrote it, and settings left at their defaults stay out of it.
+func Set(path string, key string, value interface{}) error {
+	if path == "" {
+		return errors.New("no config file path")
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) || len(bytes.TrimSpace(data)) == 0 {
+		data = []byte("{}\n")
+	} else if err != nil {
+		return err
+	}
+
+	edited, err := set(data, strings.Split(key, "."), value, "")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, edited, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// set replaces the value at keys in the JSON object obj, or adds it if it's
+// missing, without touching any of the other bytes. Indent is the indentation
+// of the line the object starts on, for laying out members added to an empty
+// object.
+func set(obj []byte, keys []string, value interface{}, indent string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(obj))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, fmt.Errorf("expected an object for %s", keys[0])
+	}
+
+	sep := "\n" + indent + "  "
+	last := int64(-1)
+	for {
+		before := dec.InputOffset()
+		if !dec.More() {
+			break
+		}
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		name, _ := t.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		end := dec.InputOffset()
+		start := end - int64(len(raw))
+
+		// The layout between members is copied from the one before.
+		between := string(obj[before:start])
+		between = between[:strings.IndexByte(between, '"')]
+		sep = strings.Replace(between, ",", "", 1)
+		last = end
+
+		if name != keys[0] {
+			continue
+		}
+		memberIndent := sep[strings.LastIndexByte(sep, '\n')+1:]
+		var replacement []byte
+		if len(keys) > 1 && bytes.HasPrefix(raw, []byte("{")) {
+			replacement, err = set(raw, keys[1:], value, memberIndent)
+		} else {
+			replacement, err = marshal(keys[1:], value, memberIndent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return splice(obj, start, end, replacement), nil
+	}
+
+	if t, err := dec.Token(); err != nil || t != json.Delim('}') {
+		return nil, fmt.Errorf("expected the end of an object for %s", keys[0])
+	}
+	memberIndent := sep[strings.LastIndexByte(sep, '\n')+1:]
+	member, err := marshal(keys[1:], value, memberIndent)
+	if err != nil {
+		return nil, err
+	}
+	name, _ := json.Marshal(keys[0])
+	member = append(append(name, ": "...), member...)
+	if last < 0 {
+		closing := dec.InputOffset() - 1
+		return splice(obj, closing, closing, []byte(sep+string(member)+"\n"+indent)), nil
+	}
+	return splice(obj, last, last, []byte(","+sep+string(member))), nil
+}
+
+// marshal lays out value, nested in an object for each of keys, to go at
+// indent.
+func marshal(keys []string, value interface{}, indent string) ([]byte, error) {
+	for i := len(keys) - 1; i >= 0; i-- {
+		value = map[string]interface{}{keys[i]: value}
+	}
+	return json.MarshalIndent(value, indent, "  ")
+}
+
+func splice(data []byte, start int64, end int64, with []byte) []byte {
+	spliced := append([]byte{}, data[:start]...)
+	spliced = append(spliced, with...)
+	return append(spliced, data[end:]...)
+}
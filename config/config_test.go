@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConfig(t *testing.T) {
+	Convey("Config", t, func() {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.json")
+
+		Convey("Missing file yields the defaults", func() {
+			c, err := Load(path)
+			So(err, ShouldBeNil)
+			So(c, ShouldResemble, Default())
+		})
+
+		Convey("File values override the defaults", func() {
+			os.WriteFile(path, []byte(`{"notifications":{"focus":{"title":"Done #{{.TomatoCount}}"}}}`), 0644)
+			c, err := Load(path)
+			So(err, ShouldBeNil)
+			So(c.Notifications.Focus.Title, ShouldEqual, "Done #{{.TomatoCount}}")
+			So(c.Notifications.ShortBreak, ShouldResemble, Default().Notifications.ShortBreak)
+		})
+
+		Convey("Data dir follows XDG_DATA_HOME", func() {
+			t.Setenv("XDG_DATA_HOME", dir)
+			d, err := DefaultDataDir()
+			So(err, ShouldBeNil)
+			So(d, ShouldEqual, filepath.Join(dir, "tomato"))
+		})
+
+		Convey("Set writes only the edited setting", func() {
+			path := filepath.Join(dir, "tomato", "config.json")
+			So(Set(path, "timer.focus", "45m"), ShouldBeNil)
+			So(Set(path, "theme", "solarized"), ShouldBeNil)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldEqual, "{\n  \"timer\": {\n    \"focus\": \"45m\"\n  },\n  \"theme\": \"solarized\"\n}\n")
+
+			loaded, err := Load(path)
+			So(err, ShouldBeNil)
+			c := Default()
+			c.Timer.Focus = "45m"
+			c.Theme = "solarized"
+			So(loaded, ShouldResemble, c)
+		})
+
+		Convey("Set keeps the rest of the file as it was", func() {
+			os.WriteFile(path, []byte("{\n\t\"theme\": \"nord\",\n\t\"timer\": {\"long_break\": \"20m\", \"focus\": \"25m\"}\n}\n"), 0644)
+			So(Set(path, "timer.focus", "50m"), ShouldBeNil)
+			So(Set(path, "timer.short_break", "10m"), ShouldBeNil)
+
+			data, _ := os.ReadFile(path)
+			So(string(data), ShouldEqual, "{\n\t\"theme\": \"nord\",\n\t\"timer\": {\"long_break\": \"20m\", \"focus\": \"50m\", \"short_break\": \"10m\"}\n}\n")
+		})
+
+		Convey("Malformed file is an error", func() {
+			os.WriteFile(path, []byte(`{`), 0644)
+			_, err := Load(path)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,98 @@
+// Package duration parses the lengths of periods as people tend to type them.
+package duration
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Max is the longest period accepted, anything longer is almost certainly a
+// typo.
+const Max = 24 * time.Hour
+
+var (
+	minutes      = regexp.MustCompile(`^\d+(\.\d+)?$`)
+	clock        = regexp.MustCompile(`^(?:(\d+):)?(\d+):(\d{2})$`)
+	hoursMinutes = regexp.MustCompile(`^(\d+)h(\d+)$`)
+)
+
+// Parse reads a duration in any of these forms:
+//
+//	25       a bare number of minutes
+//	25:00    minutes and seconds, or hours, minutes and seconds as 1:30:00
+//	1h30     hours and minutes
+//	1h30m    anything time.ParseDuration accepts
+//
+// It rejects durations that aren't more than zero, or are longer than Max.
+func Parse(s string) (time.Duration, error) {
+	d, err := parse(strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration %q must be more than zero", s)
+	}
+	if d > Max {
+		return 0, fmt.Errorf("duration %q is longer than %s", s, Format(Max))
+	}
+	return d, nil
+}
+
+// ParseOptional reads a duration like Parse, but also accepts zero, for
+// settings that zero turns off.
+func ParseOptional(s string) (time.Duration, error) {
+	if d, err := parse(strings.TrimSpace(s)); err == nil && d == 0 {
+		return 0, nil
+	}
+	return Parse(s)
+}
+
+func parse(s string) (time.Duration, error) {
+	if minutes.MatchString(s) {
+		m, _ := strconv.ParseFloat(s, 64)
+		return time.Duration(m * float64(time.Minute)).Round(time.Second), nil
+	}
+
+	if parts := clock.FindStringSubmatch(s); parts != nil {
+		h, _ := strconv.Atoi("0" + parts[1])
+		m, _ := strconv.Atoi(parts[2])
+		sec, _ := strconv.Atoi(parts[3])
+		if sec > 59 || (parts[1] != "" && m > 59) {
+			return 0, fmt.Errorf("invalid duration %q: out of range", s)
+		}
+		return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second, nil
+	}
+
+	if parts := hoursMinutes.FindStringSubmatch(s); parts != nil {
+		s = s + "m"
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q, expected minutes (25), mm:ss (25:00), 1h30 or a duration such as 1h30m", s)
+	}
+	return d, nil
+}
+
+// FormatHours formats a duration as hours and minutes, eg 2h05m, for totals
+// of time that run past a day.
+func FormatHours(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// Format drops the zero units that time.Duration prints, so that 25 minutes
+// reads as 25m rather than 25m0s.
+func Format(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
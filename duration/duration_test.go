@@ -0,0 +1,68 @@
+package duration
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDuration(t *testing.T) {
+	Convey("Duration", t, func() {
+		Convey("Parse accepts", func() {
+			cases := map[string]time.Duration{
+				"5":       5 * time.Minute,
+				"2.5":     2*time.Minute + 30*time.Second,
+				"25:00":   25 * time.Minute,
+				"1:30":    90 * time.Second,
+				"1:30:00": 90 * time.Minute,
+				"1h30":    90 * time.Minute,
+				"1h30m":   90 * time.Minute,
+				"45s":     45 * time.Second,
+				" 25m ":   25 * time.Minute,
+			}
+			for s, expected := range cases {
+				d, err := Parse(s)
+				So(err, ShouldBeNil)
+				So(d, ShouldEqual, expected)
+			}
+		})
+
+		Convey("Parse rejects", func() {
+			for _, s := range []string{"", "abc", "0", "0m", "-5m", "25h", "1:75", "1:75:00", "5 minutes"} {
+				_, err := Parse(s)
+				So(err, ShouldNotBeNil)
+			}
+		})
+
+		Convey("ParseOptional accepts zero as well", func() {
+			for _, s := range []string{"0", "0m", "0:00"} {
+				d, err := ParseOptional(s)
+				So(err, ShouldBeNil)
+				So(d, ShouldEqual, 0)
+			}
+			d, err := ParseOptional("15m")
+			So(err, ShouldBeNil)
+			So(d, ShouldEqual, 15*time.Minute)
+			_, err = ParseOptional("-5m")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Parse errors name the input", func() {
+			_, err := Parse("5 minutes")
+			So(err.Error(), ShouldContainSubstring, `"5 minutes"`)
+		})
+
+		Convey("FormatHours shows hours and minutes", func() {
+			So(FormatHours(125*time.Minute), ShouldEqual, "2h05m")
+			So(FormatHours(30*time.Hour), ShouldEqual, "30h00m")
+		})
+
+		Convey("Format drops zero units", func() {
+			So(Format(25*time.Minute), ShouldEqual, "25m")
+			So(Format(90*time.Minute), ShouldEqual, "1h30m")
+			So(Format(2*time.Hour), ShouldEqual, "2h")
+			So(Format(90*time.Second), ShouldEqual, "1m30s")
+		})
+	})
+}
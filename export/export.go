@@ -0,0 +1,207 @@
+// Package export writes recorded history in formats other tools can read.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guysherman/tomato/history"
+)
+
+// Format names one of the export formats.
+type Format string
+
+const (
+	CSV  Format = "csv"
+	JSON Format = "json"
+	ICS  Format = "ics"
+)
+
+func (f Format) Valid() bool {
+	switch f {
+	case CSV, JSON, ICS:
+		return true
+	}
+	return false
+}
+
+// Write writes the records to w in the given format.
+func Write(w io.Writer, format Format, records []history.Record) error {
+	switch format {
+	case CSV:
+		return WriteCSV(w, records)
+	case JSON:
+		return WriteJSON(w, records)
+	case ICS:
+		return WriteICS(w, records, time.Now())
+	}
+	return fmt.Errorf("unknown export format %q, expected csv, json or ics", format)
+}
+
+// row is a record as exported, with the durations in whole seconds so that
+// spreadsheets and timesheet tools can add them up.
+type row struct {
+	Start          time.Time       `json:"start"`
+	End            time.Time       `json:"end"`
+	Phase          string          `json:"phase"`
+	Task           string          `json:"task"`
+	Project        string          `json:"project"`
+	Tags           []string        `json:"tags"`
+	Outcome        history.Outcome `json:"outcome"`
+	Interruptions  int             `json:"interruptions"`
+	PlannedSeconds int             `json:"planned_seconds"`
+	ActualSeconds  int             `json:"actual_seconds"`
+	Note           string          `json:"note"`
+	Rating         int             `json:"rating"`
+}
+
+func newRow(r history.Record) row {
+	tags := r.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return row{
+		Start:          r.Start,
+		End:            r.End,
+		Phase:          r.Phase,
+		Task:           r.Task,
+		Project:        r.Project,
+		Tags:           tags,
+		Outcome:        r.Outcome,
+		Interruptions:  r.Interruptions,
+		PlannedSeconds: int(r.Planned.Seconds()),
+		ActualSeconds:  int(r.Actual.Seconds()),
+		Note:           r.Note,
+		Rating:         r.Rating,
+	}
+}
+
+var csvHeader = []string{"start", "end", "phase", "task", "project", "tags", "outcome", "interruptions", "planned_seconds", "actual_seconds", "note", "rating"}
+
+// WriteCSV writes a header, then a line per record. Tags are separated by
+// spaces.
+func WriteCSV(w io.Writer, records []history.Record) error {
+	out := csv.NewWriter(w)
+	out.Write(csvHeader)
+	for _, r := range records {
+		row := newRow(r)
+		out.Write([]string{
+			row.Start.Format(time.RFC3339),
+			row.End.Format(time.RFC3339),
+			row.Phase,
+			row.Task,
+			row.Project,
+			strings.Join(row.Tags, " "),
+			string(row.Outcome),
+			strconv.Itoa(row.Interruptions),
+			strconv.Itoa(row.PlannedSeconds),
+			strconv.Itoa(row.ActualSeconds),
+			row.Note,
+			strconv.Itoa(row.Rating),
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteJSON writes the records as a JSON array.
+func WriteJSON(w io.Writer, records []history.Record) error {
+	rows := []row{}
+	for _, r := range records {
+		rows = append(rows, newRow(r))
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rows)
+}
+
+// WriteICS writes an iCalendar file with a VEVENT for each focus period, so
+// they can be imported into a calendar. stamp is when the file was made.
+func WriteICS(w io.Writer, records []history.Record, stamp time.Time) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//guysherman//tomato//EN",
+		"CALSCALE:GREGORIAN",
+	}
+	for _, r := range records {
+		if !r.IsFocus() {
+			continue
+		}
+
+		summary := "🍅 Focus"
+		if r.Task != "" {
+			summary = "🍅 " + r.Task
+		}
+		description := fmt.Sprintf("Outcome: %s\nInterruptions: %d", r.Outcome, r.Interruptions)
+		if r.Project != "" {
+			description += "\nProject: " + r.Project
+		}
+		if len(r.Tags) > 0 {
+			description += "\nTags: " + strings.Join(r.Tags, ", ")
+		}
+		if r.Rating > 0 {
+			description += fmt.Sprintf("\nRating: %d/5", r.Rating)
+		}
+		if r.Note != "" {
+			description += "\nNote: " + r.Note
+		}
+
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%d@tomato", r.Start.UnixNano()),
+			"DTSTAMP:"+icsTime(stamp),
+			"DTSTART:"+icsTime(r.Start),
+			"DTEND:"+icsTime(r.End),
+			"SUMMARY:"+icsText(summary),
+			"DESCRIPTION:"+icsText(description),
+		)
+		if len(r.Tags) > 0 {
+			categories := []string{}
+			for _, tag := range r.Tags {
+				categories = append(categories, icsText(tag))
+			}
+			lines = append(lines, "CATEGORIES:"+strings.Join(categories, ","))
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, fold(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icsText escapes the characters that are special in iCalendar text values.
+func icsText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// fold breaks lines longer than 75 octets, as iCalendar requires, without
+// splitting a UTF-8 character.
+func fold(line string) string {
+	var b strings.Builder
+	length := 0
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > 75 {
+			b.WriteString("\r\n ")
+			length = 1
+		}
+		b.WriteRune(r)
+		length += size
+	}
+	return b.String()
+}
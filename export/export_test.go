@@ -0,0 +1,87 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guysherman/tomato/history"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestExport(t *testing.T) {
+	Convey("Export", t, func() {
+		start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+		records := []history.Record{
+			{
+				Start:         start,
+				End:           start.Add(27 * time.Minute),
+				Phase:         "focus",
+				Task:          "Fix login, again #acme",
+				Project:       "Acme",
+				Tags:          []string{"acme"},
+				Outcome:       history.Completed,
+				Interruptions: 2,
+				Planned:       25 * time.Minute,
+				Actual:        25 * time.Minute,
+				Note:          "Split the lexer out",
+				Rating:        4,
+			},
+			{
+				Start:   start.Add(27 * time.Minute),
+				End:     start.Add(32 * time.Minute),
+				Phase:   "short break",
+				Outcome: history.Completed,
+				Planned: 5 * time.Minute,
+				Actual:  5 * time.Minute,
+			},
+		}
+		out := &bytes.Buffer{}
+
+		Convey("CSV has a header and a line per period", func() {
+			So(Write(out, CSV, records), ShouldBeNil)
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			So(lines, ShouldHaveLength, 3)
+			So(lines[0], ShouldEqual, "start,end,phase,task,project,tags,outcome,interruptions,planned_seconds,actual_seconds,note,rating")
+			So(lines[1], ShouldEqual, `2026-10-19T10:00:00Z,2026-10-19T10:27:00Z,focus,"Fix login, again #acme",Acme,acme,completed,2,1500,1500,Split the lexer out,4`)
+		})
+
+		Convey("JSON is an array of periods", func() {
+			So(Write(out, JSON, records), ShouldBeNil)
+			rows := []map[string]interface{}{}
+			So(json.Unmarshal(out.Bytes(), &rows), ShouldBeNil)
+			So(rows, ShouldHaveLength, 2)
+			So(rows[0]["task"], ShouldEqual, "Fix login, again #acme")
+			So(rows[0]["project"], ShouldEqual, "Acme")
+			So(rows[0]["actual_seconds"], ShouldEqual, 1500)
+			So(rows[0]["note"], ShouldEqual, "Split the lexer out")
+			So(rows[0]["rating"], ShouldEqual, 4)
+			So(rows[1]["tags"], ShouldBeEmpty)
+		})
+
+		Convey("ICS has an event per focus period", func() {
+			So(WriteICS(out, records, start), ShouldBeNil)
+			ics := out.String()
+			So(strings.Count(ics, "BEGIN:VEVENT"), ShouldEqual, 1)
+			So(ics, ShouldStartWith, "BEGIN:VCALENDAR\r\n")
+			So(ics, ShouldContainSubstring, "DTSTART:20261019T100000Z\r\n")
+			So(ics, ShouldContainSubstring, "DTEND:20261019T102700Z\r\n")
+			So(ics, ShouldContainSubstring, `SUMMARY:🍅 Fix login\, again #acme`)
+			So(strings.ReplaceAll(ics, "\r\n ", ""), ShouldContainSubstring, `DESCRIPTION:Outcome: completed\nInterruptions: 2\nProject: Acme\nTags: acme\nRating: 4/5\nNote: Split the lexer out`)
+			So(ics, ShouldContainSubstring, "CATEGORIES:acme\r\n")
+			So(ics, ShouldEndWith, "END:VCALENDAR\r\n")
+		})
+
+		Convey("Long ICS lines are folded", func() {
+			folded := fold(strings.Repeat("a", 100))
+			So(folded, ShouldEqual, strings.Repeat("a", 75)+"\r\n "+strings.Repeat("a", 25))
+		})
+
+		Convey("Unknown formats are an error", func() {
+			So(Write(out, Format("xml"), records), ShouldNotBeNil)
+			So(Format("xml").Valid(), ShouldBeFalse)
+		})
+	})
+}
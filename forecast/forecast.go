@@ -0,0 +1,75 @@
+package forecast
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Plan is the shape of a day's cycle: the length of each period, and how many
+// tomatoes earn a long break.
+type Plan struct {
+	Focus            time.Duration
+	ShortBreak       time.Duration
+	LongBreak        time.Duration
+	LongBreakTomatos int
+}
+
+// Tomatoes counts the tomatoes that can be completed by until, given that the
+// current period ends at end and completed tomatoes are already done. If
+// inFocus is true the current period is a tomato, and counts if it ends in time.
+func (p Plan) Tomatoes(inFocus bool, end time.Time, completed int, until time.Time) int {
+	if p.Focus <= 0 || p.LongBreakTomatos <= 0 {
+		return 0
+	}
+
+	count := 0
+	at := end
+	if inFocus {
+		if at.After(until) {
+			return 0
+		}
+		count++
+		completed++
+		at = at.Add(p.breakAfter(completed))
+	}
+
+	for !at.Add(p.Focus).After(until) {
+		at = at.Add(p.Focus)
+		count++
+		completed++
+		at = at.Add(p.breakAfter(completed))
+	}
+	return count
+}
+
+func (p Plan) breakAfter(completed int) time.Duration {
+	if completed%p.LongBreakTomatos == 0 {
+		return p.LongBreak
+	}
+	return p.ShortBreak
+}
+
+// ParseClock parses a time of day such as 17:30.
+func ParseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected hh:mm", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// NextBoundary returns the earliest of the given times of day that is still to
+// come today.
+func NextBoundary(now time.Time, boundaries []time.Duration) (time.Time, bool) {
+	sorted := append([]time.Duration{}, boundaries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for _, b := range sorted {
+		if t := midnight.Add(b); t.After(now) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
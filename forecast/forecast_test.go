@@ -0,0 +1,53 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestForecast(t *testing.T) {
+	Convey("Plan", t, func() {
+		p := Plan{
+			Focus:            25 * time.Minute,
+			ShortBreak:       5 * time.Minute,
+			LongBreak:        15 * time.Minute,
+			LongBreakTomatos: 4,
+		}
+		nine := time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)
+
+		Convey("Counts whole tomatoes with their breaks", func() {
+			So(p.Tomatoes(false, nine, 0, nine.Add(time.Hour)), ShouldEqual, 2)
+		})
+
+		Convey("Counts the current tomato if it ends in time", func() {
+			So(p.Tomatoes(true, nine.Add(10*time.Minute), 0, nine.Add(40*time.Minute)), ShouldEqual, 2)
+			So(p.Tomatoes(true, nine.Add(10*time.Minute), 0, nine.Add(5*time.Minute)), ShouldEqual, 0)
+		})
+
+		Convey("Takes the long break after every fourth tomato", func() {
+			// 3 done, so the next tomato earns a long break: 25+15+25 = 65m
+			So(p.Tomatoes(false, nine, 3, nine.Add(65*time.Minute)), ShouldEqual, 2)
+			So(p.Tomatoes(false, nine, 3, nine.Add(64*time.Minute)), ShouldEqual, 1)
+		})
+	})
+
+	Convey("NextBoundary", t, func() {
+		noon, _ := ParseClock("12:00")
+		end, _ := ParseClock("17:30")
+		now := time.Date(2026, 10, 19, 13, 0, 0, 0, time.Local)
+
+		b, ok := NextBoundary(now, []time.Duration{end, noon})
+		So(ok, ShouldBeTrue)
+		So(b, ShouldEqual, time.Date(2026, 10, 19, 17, 30, 0, 0, time.Local))
+
+		_, ok = NextBoundary(now.Add(5*time.Hour), []time.Duration{end, noon})
+		So(ok, ShouldBeFalse)
+	})
+
+	Convey("ParseClock rejects nonsense", t, func() {
+		_, err := ParseClock("25:00")
+		So(err, ShouldNotBeNil)
+	})
+}
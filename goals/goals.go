@@ -0,0 +1,130 @@
+package goals
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/guysherman/tomato/duration"
+	"github.com/guysherman/tomato/history"
+)
+
+// Goals are the targets to aim for: Daily tomatos a day, and Weekly of focused
+// time from Monday to Sunday. A goal of zero isn't tracked.
+type Goals struct {
+	Daily  int
+	Weekly time.Duration
+}
+
+// Progress is how far along the goals are. Streak and Longest are runs of
+// days on which the daily goal was met, or on which there was a tomato at all
+// without one.
+type Progress struct {
+	Today   int
+	Week    time.Duration
+	Streak  int
+	Longest int
+}
+
+// Measure works out the progress made towards the goals from the history, as
+// of now.
+func (g Goals) Measure(records []history.Record, now time.Time) Progress {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	week := history.StartOfWeek(now)
+
+	p := Progress{}
+	for _, r := range records {
+		if !r.IsFocus() || r.Outcome != history.Completed {
+			continue
+		}
+		start := r.Start.In(now.Location())
+		if !start.Before(today) {
+			p.Today++
+		}
+		if !start.Before(week) {
+			p.Week += r.Actual
+		}
+	}
+	p.Streak, p.Longest = history.Streaks(records, g.Daily, now)
+	return p
+}
+
+// Set reports whether there are any goals.
+func (g Goals) Set() bool {
+	return g.Daily > 0 || g.Weekly > 0
+}
+
+// DailyMet reports whether the daily goal has been reached.
+func (g Goals) DailyMet(p Progress) bool {
+	return g.Daily > 0 && p.Today >= g.Daily
+}
+
+// Summary describes the progress on a single line, eg
+// "3/8 🍅 today   12h30m/20h00m this week   4 day streak".
+func (g Goals) Summary(p Progress) string {
+	parts := []string{}
+	if g.Daily > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d 🍅 today", p.Today, g.Daily))
+	}
+	if g.Weekly > 0 {
+		parts = append(parts, fmt.Sprintf("%s/%s this week", duration.FormatHours(p.Week), duration.FormatHours(g.Weekly)))
+	}
+	if p.Streak > 0 {
+		parts = append(parts, fmt.Sprintf("%d day streak", p.Streak))
+	}
+	return strings.Join(parts, "   ")
+}
+
+// Budgets are the most time to spend on each project in a week, from Monday
+// to Sunday.
+type Budgets map[string]time.Duration
+
+// Budget is the time spent on a project so far this week, against its budget.
+type Budget struct {
+	Project string
+	Spent   time.Duration
+	Budget  time.Duration
+}
+
+// Over reports whether more time has been spent than was budgeted.
+func (b Budget) Over() bool {
+	return b.Spent > b.Budget
+}
+
+// String describes the budget, eg "acme 10h25m of 10h00m this week, over
+// budget".
+func (b Budget) String() string {
+	s := fmt.Sprintf("%s %s of %s this week", b.Project, duration.FormatHours(b.Spent), duration.FormatHours(b.Budget))
+	if b.Over() {
+		s += ", over budget"
+	}
+	return s
+}
+
+// Measure totals the time focused on each project with a budget so far this
+// week, ordered by project.
+func (b Budgets) Measure(records []history.Record, now time.Time) []Budget {
+	week := history.StartOfWeek(now)
+	spent := map[string]time.Duration{}
+	for _, r := range records {
+		if r.IsFocus() && r.Outcome == history.Completed && !r.Start.In(now.Location()).Before(week) {
+			spent[r.Project] += r.Actual
+		}
+	}
+
+	budgets := []Budget{}
+	for project, budget := range b {
+		budgets = append(budgets, Budget{Project: project, Spent: spent[project], Budget: budget})
+	}
+	sort.Slice(budgets, func(i, j int) bool { return budgets[i].Project < budgets[j].Project })
+	return budgets
+}
+
+// For is the budget for the project this week, if it has one.
+func (b Budgets) For(project string, records []history.Record, now time.Time) (Budget, bool) {
+	if _, ok := b[project]; !ok {
+		return Budget{}, false
+	}
+	return Budgets{project: b[project]}.Measure(records, now)[0], true
+}
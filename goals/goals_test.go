@@ -0,0 +1,57 @@
+package goals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guysherman/tomato/history"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGoals(t *testing.T) {
+	Convey("Goals", t, func() {
+		focus := func(start time.Time) history.Record {
+			return history.Record{Start: start, Phase: "focus", Outcome: history.Completed, Actual: 25 * time.Minute}
+		}
+		records := []history.Record{
+			focus(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)),
+			focus(time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)),
+			focus(time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)),
+			focus(time.Date(2026, 10, 21, 9, 0, 0, 0, time.UTC)),
+			focus(time.Date(2026, 10, 21, 10, 0, 0, 0, time.UTC)),
+			{Start: time.Date(2026, 10, 21, 11, 0, 0, 0, time.UTC), Phase: "focus", Outcome: history.Stopped, Actual: 10 * time.Minute},
+		}
+		now := time.Date(2026, 10, 21, 12, 0, 0, 0, time.UTC)
+		g := Goals{Daily: 2, Weekly: 20 * time.Hour}
+
+		Convey("measure today, the week from Monday, and the streak", func() {
+			p := g.Measure(records, now)
+			So(p, ShouldResemble, Progress{Today: 2, Week: 100 * time.Minute, Streak: 1, Longest: 1})
+			So(g.DailyMet(p), ShouldBeTrue)
+			So(g.Summary(p), ShouldEqual, "2/2 🍅 today   1h40m/20h00m this week   1 day streak")
+		})
+
+		Convey("budgets total this week's time per project", func() {
+			records[3].Project = "acme"
+			records[4].Project = "acme"
+			records[1].Project = "globex"
+			budgets := Budgets{"acme": 40 * time.Minute, "globex": time.Hour, "initech": time.Hour}.Measure(records, now)
+
+			So(budgets, ShouldHaveLength, 3)
+			So(budgets[0], ShouldResemble, Budget{Project: "acme", Spent: 50 * time.Minute, Budget: 40 * time.Minute})
+			So(budgets[0].String(), ShouldEqual, "acme 0h50m of 0h40m this week, over budget")
+			So(budgets[1].Over(), ShouldBeFalse)
+			So(budgets[2].Spent, ShouldEqual, 0)
+
+			_, ok := Budgets{"acme": time.Hour}.For("globex", records, now)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("without a daily goal, any tomato keeps the streak going", func() {
+			p := Goals{}.Measure(records, now)
+			So(p.Streak, ShouldEqual, 3)
+			So(Goals{}.Set(), ShouldBeFalse)
+			So(Goals{}.DailyMet(p), ShouldBeFalse)
+		})
+	})
+}
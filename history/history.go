@@ -0,0 +1,292 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Outcome is how a period ended.
+type Outcome string
+
+const (
+	Completed Outcome = "completed"
+	Stopped   Outcome = "stopped"
+	Skipped   Outcome = "skipped"
+)
+
+// Record is one focus or break period. Planned is the length the period was
+// set up with, and Actual the time the timer actually ran for, which leaves
+// out time spent paused and includes any time nudged on. Project is the
+// project or client a focus period is billed to, and Tags are the #hashtags
+// in its task name, along with any set on their own. Note and Rating, from 1
+// to 5, are filled in by the reflection after a focus period.
+type Record struct {
+	Start         time.Time     `json:"start"`
+	End           time.Time     `json:"end"`
+	Phase         string        `json:"phase"`
+	Task          string        `json:"task,omitempty"`
+	Project       string        `json:"project,omitempty"`
+	Tags          []string      `json:"tags,omitempty"`
+	Outcome       Outcome       `json:"outcome"`
+	Interruptions int           `json:"interruptions"`
+	Planned       time.Duration `json:"planned"`
+	Actual        time.Duration `json:"actual"`
+	Note          string        `json:"note,omitempty"`
+	Rating        int           `json:"rating,omitempty"`
+}
+
+// IsFocus reports whether the record is of a focus period.
+func (r Record) IsFocus() bool {
+	return r.Phase == "focus"
+}
+
+// TagsFor is the tags in a task name along with the extra ones, without
+// repeats.
+func TagsFor(task string, extra []string) []string {
+	tags := TagsIn(task)
+	for _, tag := range extra {
+		if !contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// TagsIn finds the #hashtags in a task name, without the #, eg "Fix login
+// #acme #bug" is tagged acme and bug.
+func TagsIn(task string) []string {
+	tags := []string{}
+	for _, word := range strings.Fields(task) {
+		if len(word) > 1 && strings.HasPrefix(word, "#") {
+			tags = append(tags, strings.TrimPrefix(word, "#"))
+		}
+	}
+	return tags
+}
+
+// Store keeps records in a file, one JSON record per line. A Store with no
+// Path keeps nothing, which is handy for tests.
+type Store struct {
+	Path string
+}
+
+// Append adds a record to the end of the file, creating it if needed.
+func (s Store) Append(r Record) error {
+	if s.Path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// SkippedError is returned by Load, along with every record it could read,
+// when some lines of the file couldn't be read. Lines are numbered from 1.
+type SkippedError struct {
+	Path  string
+	Lines []int
+}
+
+func (e *SkippedError) Error() string {
+	lines := make([]string, len(e.Lines))
+	for i, n := range e.Lines {
+		lines[i] = strconv.Itoa(n)
+	}
+	return fmt.Sprintf("skipped unreadable lines in %s: %s", e.Path, strings.Join(lines, ", "))
+}
+
+// Load reads every record in the file. A missing file just has no records.
+// Lines that can't be read are skipped, and reported in a *SkippedError.
+func (s Store) Load() ([]Record, error) {
+	records := []Record{}
+	lines, err := s.lines()
+	if err != nil {
+		return records, err
+	}
+
+	skipped := &SkippedError{Path: s.Path}
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(line, &r); err != nil {
+			skipped.Lines = append(skipped.Lines, i+1)
+			continue
+		}
+		records = append(records, r)
+	}
+	if len(skipped.Lines) > 0 {
+		return records, skipped
+	}
+	return records, nil
+}
+
+// lines reads the lines of the file, however long they are.
+func (s Store) lines() ([][]byte, error) {
+	if s.Path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lines := [][]byte{}
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			lines = append(lines, bytes.TrimSuffix(line, []byte("\n")))
+		}
+		if err == io.EOF {
+			return lines, nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Annotate adds a note and rating to the record that started at start,
+// rewriting the file. Lines that can't be read are kept as they are.
+func (s Store) Annotate(start time.Time, note string, rating int) error {
+	if s.Path == "" {
+		return nil
+	}
+
+	lines, err := s.lines()
+	if err != nil {
+		return err
+	}
+	found := false
+	var b bytes.Buffer
+	for _, line := range lines {
+		var r Record
+		if json.Unmarshal(line, &r) == nil && r.Start.Equal(start) {
+			r.Note = note
+			r.Rating = rating
+			found = true
+			if line, err = json.Marshal(r); err != nil {
+				return err
+			}
+		}
+		b.Write(append(line, '\n'))
+	}
+	if !found {
+		return fmt.Errorf("no record started at %s", start.Format(time.RFC3339))
+	}
+
+	// Write a new file and move it into place, so the history isn't lost if
+	// writing fails part way.
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, b.Bytes(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
+
+// Between returns the records that started in [since, until).
+func Between(records []Record, since time.Time, until time.Time) []Record {
+	between := []Record{}
+	for _, r := range records {
+		if !r.Start.Before(since) && r.Start.Before(until) {
+			between = append(between, r)
+		}
+	}
+	return between
+}
+
+// Streaks counts the runs of consecutive days on which at least goal focus
+// periods were completed, or at least one if goal is zero. current is the run
+// that reaches today, or yesterday while today's goal is still to be met, and
+// longest is the longest run there has been.
+func Streaks(records []Record, goal int, now time.Time) (current int, longest int) {
+	if goal < 1 {
+		goal = 1
+	}
+	perDay := map[string]int{}
+	for _, r := range records {
+		if r.IsFocus() && r.Outcome == Completed {
+			perDay[DayKey(r.Start.In(now.Location()))]++
+		}
+	}
+
+	days := []time.Time{}
+	for day, count := range perDay {
+		if count >= goal {
+			t, _ := time.Parse("2006-01-02", day)
+			days = append(days, t)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	run := 0
+	for i, day := range days {
+		if i > 0 && days[i-1].AddDate(0, 0, 1).Equal(day) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+
+	day := now
+	if perDay[DayKey(day)] < goal {
+		day = day.AddDate(0, 0, -1)
+	}
+	for perDay[DayKey(day)] >= goal {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+	return current, longest
+}
+
+// StartOfWeek is midnight on the Monday of the week t is in.
+func StartOfWeek(t time.Time) time.Time {
+	today := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+}
+
+// DayKey names the day t falls on, for counting records by day.
+func DayKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,132 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHistory(t *testing.T) {
+	Convey("History", t, func() {
+		dir := t.TempDir()
+		store := Store{Path: filepath.Join(dir, "tomato", "history.jsonl")}
+		start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+		record := Record{
+			Start:         start,
+			End:           start.Add(27 * time.Minute),
+			Phase:         "focus",
+			Task:          "Refactor parser",
+			Outcome:       Completed,
+			Interruptions: 1,
+			Planned:       25 * time.Minute,
+			Actual:        25 * time.Minute,
+		}
+
+		Convey("Missing file has no records", func() {
+			records, err := store.Load()
+			So(err, ShouldBeNil)
+			So(records, ShouldBeEmpty)
+		})
+
+		Convey("Appended records are loaded back", func() {
+			So(store.Append(record), ShouldBeNil)
+			So(store.Append(Record{Start: start.Add(time.Hour), Phase: "short break", Outcome: Skipped}), ShouldBeNil)
+
+			records, err := store.Load()
+			So(err, ShouldBeNil)
+			So(records, ShouldHaveLength, 2)
+			So(records[0], ShouldResemble, record)
+			So(records[0].IsFocus(), ShouldBeTrue)
+			So(records[1].IsFocus(), ShouldBeFalse)
+		})
+
+		Convey("Records can be annotated with a note and rating", func() {
+			store.Append(record)
+			store.Append(Record{Start: start.Add(time.Hour), Phase: "focus"})
+			So(store.Annotate(start, "Split the lexer out", 4), ShouldBeNil)
+
+			records, err := store.Load()
+			So(err, ShouldBeNil)
+			So(records[0].Note, ShouldEqual, "Split the lexer out")
+			So(records[0].Rating, ShouldEqual, 4)
+			So(records[1].Note, ShouldBeEmpty)
+
+			So(store.Annotate(start.Add(time.Minute), "", 0), ShouldNotBeNil)
+		})
+
+		Convey("A store without a path keeps nothing", func() {
+			So(Store{}.Append(record), ShouldBeNil)
+			records, err := Store{}.Load()
+			So(err, ShouldBeNil)
+			So(records, ShouldBeEmpty)
+		})
+
+		Convey("Malformed lines are skipped and reported", func() {
+			store.Append(record)
+			f, _ := os.OpenFile(store.Path, os.O_APPEND|os.O_WRONLY, 0644)
+			f.WriteString("{\n")
+			f.Close()
+			store.Append(Record{Start: start.Add(time.Hour), Phase: "focus", Note: strings.Repeat("x", 100*1024)})
+
+			records, err := store.Load()
+			So(records, ShouldHaveLength, 2)
+			var skipped *SkippedError
+			So(errors.As(err, &skipped), ShouldBeTrue)
+			So(skipped.Lines, ShouldResemble, []int{2})
+
+			So(store.Annotate(start, "Split the lexer out", 4), ShouldBeNil)
+			data, _ := os.ReadFile(store.Path)
+			So(strings.Split(string(data), "\n")[1], ShouldEqual, "{")
+		})
+
+		Convey("Tags are the hashtags in the task name", func() {
+			So(TagsIn("Fix login #acme #bug"), ShouldResemble, []string{"acme", "bug"})
+			So(TagsIn("Fix issue # 12"), ShouldBeEmpty)
+			So(TagsFor("Fix login #acme", []string{"bug", "acme"}), ShouldResemble, []string{"acme", "bug"})
+		})
+
+		Convey("Between picks records by start time", func() {
+			records := []Record{record, {Start: start.Add(24 * time.Hour)}}
+			So(Between(records, start, start.Add(time.Hour)), ShouldResemble, []Record{record})
+		})
+
+		Convey("Streaks count the days in a row the goal was met", func() {
+			day := func(d int, tomatos int) []Record {
+				records := []Record{}
+				for i := 0; i < tomatos; i++ {
+					records = append(records, Record{Start: time.Date(2026, 10, d, 9+i, 0, 0, 0, time.UTC), Phase: "focus", Outcome: Completed})
+				}
+				return records
+			}
+			records := append(day(10, 2), day(11, 2)...)
+			records = append(records, day(12, 2)...)
+			records = append(records, day(13, 1)...)
+			records = append(records, day(17, 2)...)
+			records = append(records, day(18, 2)...)
+			records = append(records, Record{Start: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC), Phase: "focus", Outcome: Stopped})
+			now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+			current, longest := Streaks(records, 2, now)
+			So(current, ShouldEqual, 2)
+			So(longest, ShouldEqual, 3)
+
+			current, longest = Streaks(records, 0, now)
+			So(current, ShouldEqual, 2)
+			So(longest, ShouldEqual, 4)
+
+			current, _ = Streaks(append(records, day(19, 2)...), 2, now)
+			So(current, ShouldEqual, 3)
+		})
+
+		Convey("StartOfWeek is the Monday before", func() {
+			monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+			So(StartOfWeek(time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC)), ShouldEqual, monday)
+			So(StartOfWeek(monday.Add(time.Hour)), ShouldEqual, monday)
+		})
+	})
+}
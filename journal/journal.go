@@ -0,0 +1,223 @@
+// Package journal appends completed focus periods to daily Org or Markdown
+// notes.
+package journal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/guysherman/tomato/history"
+)
+
+// Format names the kind of notes the journal is kept in.
+type Format string
+
+const (
+	Org      Format = "org"
+	Markdown Format = "markdown"
+)
+
+func (f Format) Valid() bool {
+	switch f {
+	case Org, Markdown:
+		return true
+	}
+	return false
+}
+
+// Journal appends each completed focus period to a daily file. The path is a
+// text/template executed with the time the period started, so
+// "~/notes/{{.Format \"2006-01-02\"}}.md" gives a file per day. The zero
+// Journal writes nothing.
+type Journal struct {
+	Format Format
+	path   *template.Template
+}
+
+// New checks the format and parses the path template.
+func New(format Format, path string) (Journal, error) {
+	if !format.Valid() {
+		return Journal{}, fmt.Errorf("unknown journal format %q, expected org or markdown", format)
+	}
+	if strings.TrimSpace(path) == "" {
+		return Journal{}, errors.New("the journal needs a path")
+	}
+	tmpl, err := template.New("path").Parse(path)
+	if err != nil {
+		return Journal{}, err
+	}
+	return Journal{Format: format, path: tmpl}, nil
+}
+
+// Path is the file the journal for the given day is kept in.
+func (j Journal) Path(t time.Time) (string, error) {
+	var b bytes.Buffer
+	if err := j.path.Execute(&b, t); err != nil {
+		return "", err
+	}
+
+	path := b.String()
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, path[2:])
+	}
+	return path, nil
+}
+
+// Write adds the record to the journal for the day it started on.
+func (j Journal) Write(r history.Record) error {
+	if j.path == nil {
+		return nil
+	}
+
+	path, err := j.Path(r.Start)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if j.Format == Org {
+		return writeClock(path, r)
+	}
+	return appendBullet(path, r)
+}
+
+// appendBullet adds the record's bullet to the end of a Markdown file, without
+// rewriting what's already there.
+func appendBullet(path string, r history.Record) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+
+	line := Bullet(r) + "\n"
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+			f.Close()
+			return err
+		}
+		if last[0] != '\n' {
+			line = "\n" + line
+		}
+	}
+
+	if _, err := f.WriteString(line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeClock adds the record's CLOCK line to an Org file. The heading can be
+// anywhere in the file, so it's rewritten, to a new file that's moved into
+// place so the notes aren't lost if writing fails part way.
+func writeClock(path string, r history.Record) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(AddClock(string(existing), r)), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func taskName(r history.Record) string {
+	if r.Task == "" {
+		return "Focus"
+	}
+	return r.Task
+}
+
+// Bullet is the Markdown line for a record, eg
+// "- 10:00–10:25 🍅 Refactor parser (2 interruptions)".
+func Bullet(r history.Record) string {
+	line := fmt.Sprintf("- %s–%s 🍅 %s", r.Start.Format("15:04"), r.End.Format("15:04"), taskName(r))
+	switch r.Interruptions {
+	case 0:
+	case 1:
+		line += " (1 interruption)"
+	default:
+		line += fmt.Sprintf(" (%d interruptions)", r.Interruptions)
+	}
+	return line
+}
+
+// Clock is the Org CLOCK line for a record.
+func Clock(r history.Record) string {
+	d := r.End.Sub(r.Start).Round(time.Minute)
+	return fmt.Sprintf("CLOCK: %s--%s => %2d:%02d", orgTime(r.Start), orgTime(r.End), int(d.Hours()), int(d.Minutes())%60)
+}
+
+func orgTime(t time.Time) string {
+	return t.Format("[2006-01-02 Mon 15:04]")
+}
+
+var (
+	heading  = regexp.MustCompile(`^(\*+)\s+(?:(?:TODO|NEXT|DONE)\s+)?(.*?)(?:\s+:[\w@#%:]+:)?\s*$`)
+	planning = regexp.MustCompile(`^\s*(SCHEDULED|DEADLINE|CLOSED):`)
+)
+
+// AddClock adds the record's CLOCK line to the LOGBOOK of the heading for its
+// task, adding the heading at the end of the file if there isn't one.
+func AddClock(doc string, r history.Record) string {
+	lines := strings.Split(strings.TrimSuffix(doc, "\n"), "\n")
+	if doc == "" {
+		lines = nil
+	}
+	clock := Clock(r)
+
+	at := -1
+	for i, line := range lines {
+		if parts := heading.FindStringSubmatch(line); parts != nil && parts[2] == taskName(r) {
+			at = i
+			break
+		}
+	}
+	if at == -1 {
+		lines = append(lines, "* "+taskName(r), ":LOGBOOK:", clock, ":END:")
+		return strings.Join(lines, "\n") + "\n"
+	}
+
+	// The LOGBOOK goes after any planning line and PROPERTIES drawer.
+	i := at + 1
+	if i < len(lines) && planning.MatchString(lines[i]) {
+		i++
+	}
+	if i < len(lines) && strings.TrimSpace(lines[i]) == ":PROPERTIES:" {
+		for i < len(lines) && strings.TrimSpace(lines[i]) != ":END:" {
+			i++
+		}
+		i++
+	}
+
+	insert := []string{":LOGBOOK:", clock, ":END:"}
+	if i < len(lines) && strings.TrimSpace(lines[i]) == ":LOGBOOK:" {
+		i++
+		insert = []string{clock}
+	}
+	if i > len(lines) {
+		i = len(lines)
+	}
+	lines = append(lines[:i], append(insert, lines[i:]...)...)
+	return strings.Join(lines, "\n") + "\n"
+}
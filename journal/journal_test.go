@@ -0,0 +1,117 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guysherman/tomato/history"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestJournal(t *testing.T) {
+	Convey("Journal", t, func() {
+		start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+		r := history.Record{
+			Start:         start,
+			End:           start.Add(25 * time.Minute),
+			Phase:         "focus",
+			Task:          "Refactor parser",
+			Outcome:       history.Completed,
+			Interruptions: 2,
+		}
+
+		Convey("Markdown", func() {
+			Convey("bullets show the times, task and interruptions", func() {
+				So(Bullet(r), ShouldEqual, "- 10:00–10:25 🍅 Refactor parser (2 interruptions)")
+				r.Interruptions = 1
+				So(Bullet(r), ShouldEqual, "- 10:00–10:25 🍅 Refactor parser (1 interruption)")
+				r.Interruptions = 0
+				r.Task = ""
+				So(Bullet(r), ShouldEqual, "- 10:00–10:25 🍅 Focus")
+			})
+
+			Convey("bullets are appended on a line of their own", func() {
+				path := filepath.Join(t.TempDir(), "notes.md")
+				os.WriteFile(path, []byte("# Monday"), 0644)
+				So(appendBullet(path, r), ShouldBeNil)
+
+				data, _ := os.ReadFile(path)
+				So(string(data), ShouldEqual, "# Monday\n- 10:00–10:25 🍅 Refactor parser (2 interruptions)\n")
+			})
+		})
+
+		Convey("Org", func() {
+			clock := "CLOCK: [2026-10-19 Mon 10:00]--[2026-10-19 Mon 10:25] =>  0:25"
+
+			Convey("clock lines show the times and duration", func() {
+				So(Clock(r), ShouldEqual, clock)
+			})
+
+			Convey("a heading is added for a new task", func() {
+				So(AddClock("#+TITLE: Monday\n", r), ShouldEqual, "#+TITLE: Monday\n* Refactor parser\n:LOGBOOK:\n"+clock+"\n:END:\n")
+			})
+
+			Convey("clock lines go in the task's LOGBOOK", func() {
+				doc := "* TODO Refactor parser :work:\n:LOGBOOK:\nCLOCK: earlier\n:END:\n* Other\n"
+				So(AddClock(doc, r), ShouldEqual, "* TODO Refactor parser :work:\n:LOGBOOK:\n"+clock+"\nCLOCK: earlier\n:END:\n* Other\n")
+			})
+
+			Convey("a LOGBOOK is added after planning and properties", func() {
+				doc := "** Refactor parser\nSCHEDULED: <2026-10-19 Mon>\n:PROPERTIES:\n:ID: 1\n:END:\nNotes\n"
+				So(AddClock(doc, r), ShouldEqual, "** Refactor parser\nSCHEDULED: <2026-10-19 Mon>\n:PROPERTIES:\n:ID: 1\n:END:\n:LOGBOOK:\n"+clock+"\n:END:\nNotes\n")
+			})
+		})
+
+		Convey("Files", func() {
+			dir := t.TempDir()
+
+			Convey("are named from the date the period started", func() {
+				j, err := New(Markdown, filepath.Join(dir, `{{.Format "2006-01-02"}}.md`))
+				So(err, ShouldBeNil)
+				So(j.Write(r), ShouldBeNil)
+				So(j.Write(r), ShouldBeNil)
+
+				data, err := os.ReadFile(filepath.Join(dir, "2026-10-19.md"))
+				So(err, ShouldBeNil)
+				So(string(data), ShouldEqual, Bullet(r)+"\n"+Bullet(r)+"\n")
+			})
+
+			Convey("Markdown is appended to what's there", func() {
+				path := filepath.Join(dir, "notes.md")
+				os.WriteFile(path, []byte("# Monday"), 0644)
+				j, _ := New(Markdown, path)
+				So(j.Write(r), ShouldBeNil)
+
+				data, _ := os.ReadFile(path)
+				So(string(data), ShouldEqual, "# Monday\n"+Bullet(r)+"\n")
+			})
+
+			Convey("Org is rewritten with the clock line", func() {
+				path := filepath.Join(dir, "notes.org")
+				os.WriteFile(path, []byte("* Refactor parser\n"), 0644)
+				j, _ := New(Org, path)
+				So(j.Write(r), ShouldBeNil)
+
+				data, _ := os.ReadFile(path)
+				So(string(data), ShouldEqual, AddClock("* Refactor parser\n", r))
+				_, err := os.Stat(path + ".tmp")
+				So(os.IsNotExist(err), ShouldBeTrue)
+			})
+
+			Convey("bad formats, templates and paths are errors", func() {
+				_, err := New("txt", "notes.txt")
+				So(err, ShouldNotBeNil)
+				_, err = New(Org, "{{.Format")
+				So(err, ShouldNotBeNil)
+				_, err = New(Markdown, " ")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("the zero journal writes nothing", func() {
+				So(Journal{}.Write(r), ShouldBeNil)
+			})
+		})
+	})
+}
@@ -1,12 +1,23 @@
 package main
 
 import (
-	"flag"
 	"fmt"
 	"os"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guysherman/tomato/config"
+	"github.com/guysherman/tomato/duration"
+	"github.com/guysherman/tomato/forecast"
+	"github.com/guysherman/tomato/goals"
+	"github.com/guysherman/tomato/history"
+	"github.com/guysherman/tomato/journal"
+	"github.com/guysherman/tomato/notifications"
+	"github.com/guysherman/tomato/screens"
+	"github.com/guysherman/tomato/terminal"
+	"github.com/guysherman/tomato/theme"
 	"github.com/guysherman/tomato/timerview"
 )
 
@@ -18,34 +29,164 @@ const (
 	longBreak
 )
 
+// Title is the name of the mode as shown on screen.
+func (t timerMode) Title() string {
+	switch t {
+	case shortBreak:
+		return "Short Break"
+	case longBreak:
+		return "Long Break"
+	default:
+		return "Focus"
+	}
+}
+
+func (t timerMode) String() string {
+	switch t {
+	case shortBreak:
+		return "short break"
+	case longBreak:
+		return "long break"
+	default:
+		return "focus"
+	}
+}
+
 type Tomato struct {
-	currentView      View
-	mode             timerMode
-	tomatoCount      int
-	currentWidth     int
-	currentHeight    int
-	focusTime        string
-	shortBreakTime   string
-	longBreakTime    string
-	longBreakTomatos int
-	quietModeScript  string
-	noiseModeScript  string
+	currentView            View
+	mode                   timerMode
+	tomatoCount            int
+	voidedCount            int
+	todayCount             int
+	today                  string
+	currentWidth           int
+	currentHeight          int
+	focusTime              time.Duration
+	shortBreakTime         time.Duration
+	longBreakTime          time.Duration
+	longBreakTomatos       int
+	quietModeScript        string
+	noiseModeScript        string
+	task                   string
+	project                string
+	tags                   []string
+	budgets                goals.Budgets
+	labelling              bool
+	label                  screens.Label
+	nextLabel              *screens.LabelledMsg
+	focusNotification      notifications.Template
+	shortBreakNotification notifications.Template
+	longBreakNotification  notifications.Template
+	goalNotification       notifications.Template
+	budgetNotification     notifications.Template
+	output                 *terminal.Output
+	notifier               notifications.Notifier
+	warnings               []warning
+	reporter               terminal.Reporter
+	font                   string
+	boundaries             []time.Duration
+	dayStart               time.Duration
+	dayEnd                 time.Duration
+	theme                  theme.Theme
+	keys                   timerview.KeyMap
+	layout                 timerview.Layout
+	inline                 bool
+	once                   bool
+	completed              bool
+	periodEnded            bool
+	summary                string
+	history                history.Store
+	journal                journal.Journal
+	reflect                bool
+	goals                  goals.Goals
+	reflecting             bool
+	reflection             screens.Reflection
+	reflectionFor          time.Time
+	tab                    screens.Tab
+	plan                   screens.Plan
+	tasks                  screens.Tasks
+	stats                  screens.Stats
+	settings               screens.Settings
+	configPath             string
+	dataDir                string
+	config                 config.Config
+}
+
+// minTabsHeight is the smallest window that has room for the tab bar. Smaller
+// windows only show the timer.
+const minTabsHeight = 10
+
+type clockMsg struct{}
+
+// clockTick keeps the view fresh while the timer isn't ticking, so that the
+// time the period ends at moves on while paused.
+func clockTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return clockMsg{}
+	})
+}
+
+type warning struct {
+	phase    string
+	before   time.Duration
+	template notifications.Template
+	hook     string
+}
+
+// appliesTo reports whether the warning was configured for the given mode.
+func (w warning) appliesTo(mode timerMode) bool {
+	switch w.phase {
+	case "":
+		return true
+	case "break":
+		return mode != focus
+	case "focus":
+		return mode == focus
+	case "short_break":
+		return mode == shortBreak
+	case "long_break":
+		return mode == longBreak
+	}
+	return false
 }
 
 func (m Tomato) Init() tea.Cmd {
-	return nil
+	if m.once {
+		return tea.Batch(clockTick(), startTimer)
+	}
+	return clockTick()
+}
+
+func startTimer() tea.Msg {
+	return timerview.StartMsg{}
 }
 
 func (m Tomato) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case timerview.TimerCompleteMsg:
 		return handleTimerComplete(m, msg)
+	case timerview.TimerVoidedMsg:
+		return handleTimerVoided(m, msg)
+	case screens.TaskSelectedMsg:
+		return handleTaskSelected(m, msg)
+	case screens.SettingChangedMsg:
+		return handleSettingChanged(m, msg)
+	case screens.ReflectionDoneMsg:
+		return handleReflectionDone(m, msg)
+	case screens.PlanChangedMsg:
+		return handlePlanChanged(m, msg)
+	case screens.LabelledMsg:
+		return handleLabelled(m, msg)
+	case clockMsg:
+		return m, clockTick()
+	case tea.KeyMsg:
+		return handleKeyMessage(m, msg)
+	case tea.MouseMsg:
+		return handleMouseMessage(m, msg)
 	case tea.WindowSizeMsg:
-		var cmd tea.Cmd
 		m.currentWidth = msg.Width
 		m.currentHeight = msg.Height
-		m.currentView, cmd = m.currentView.Update(msg)
-		return m, cmd
+		return handleResize(m)
 	default:
 		var cmd tea.Cmd
 		m.currentView, cmd = m.currentView.Update(msg)
@@ -53,62 +194,714 @@ func (m Tomato) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// handleKeyMessage switches tabs, and otherwise passes the key on to the
+// visible screen.
+func handleKeyMessage(m Tomato, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.reflecting {
+		var cmd tea.Cmd
+		m.reflection, cmd = m.reflection.Update(msg)
+		return m, cmd
+	}
+	if m.labelling {
+		var cmd tea.Cmd
+		m.label, cmd = m.label.Update(msg)
+		return m, cmd
+	}
+
+	tab := m.visibleTab()
+	if tab == screens.PlanTab && m.plan.Editing() {
+		var cmd tea.Cmd
+		m.plan, cmd = m.plan.Update(msg)
+		return m, cmd
+	}
+	if tab == screens.TasksTab && m.tasks.Editing() {
+		var cmd tea.Cmd
+		m.tasks, cmd = m.tasks.Update(msg)
+		return m, cmd
+	}
+	if tab == screens.SettingsTab && m.settings.Editing() {
+		var cmd tea.Cmd
+		m.settings, cmd = m.settings.Update(msg)
+		return m, cmd
+	}
+
+	switch {
+	case m.showTabs() && key.Matches(msg, m.keys.NextTab):
+		m.tab = m.tab.Next()
+		return m, nil
+	case m.showTabs() && key.Matches(msg, m.keys.PrevTab):
+		m.tab = m.tab.Prev()
+		return m, nil
+	case tab != screens.TimerTab && key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case tab == screens.TimerTab && !m.inline && key.Matches(msg, m.keys.Label):
+		m.labelling = true
+		project, tags := m.project, m.tags
+		if m.nextLabel != nil {
+			project, tags = m.nextLabel.Project, m.nextLabel.Tags
+		}
+		m.label = screens.NewLabel(project, tags).SetKeys(screens.NewLabelKeyMap(m.keys))
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	switch tab {
+	case screens.PlanTab:
+		m.plan, cmd = m.plan.Update(msg)
+	case screens.TasksTab:
+		m.tasks, cmd = m.tasks.Update(msg)
+	case screens.StatsTab:
+		m.stats, cmd = m.stats.Update(msg)
+	case screens.SettingsTab:
+		m.settings, cmd = m.settings.Update(msg)
+	default:
+		m.currentView, cmd = m.currentView.Update(msg)
+	}
+	return m, cmd
+}
+
+// handleMouseMessage passes mouse events to the timer when it's visible,
+// moving them up past the tab bar.
+func handleMouseMessage(m Tomato, msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	// The reflection and label prompts cover the timer, so clicks mustn't
+	// reach the buttons underneath.
+	if m.visibleTab() != screens.TimerTab || m.reflecting || m.labelling {
+		return m, nil
+	}
+	if m.showTabs() {
+		msg.Y -= screens.TabBarHeight
+	}
+
+	var cmd tea.Cmd
+	m.currentView, cmd = m.currentView.Update(msg)
+	return m, cmd
+}
+
+// handleResize passes the room left under the tab bar on to every screen,
+// not just the visible one.
+func handleResize(m Tomato) (tea.Model, tea.Cmd) {
+	size := tea.WindowSizeMsg{Width: m.currentWidth, Height: m.screenHeight()}
+	var cmd tea.Cmd
+	m.currentView, cmd = m.currentView.Update(size)
+	m.plan, _ = m.plan.Update(size)
+	m.tasks, _ = m.tasks.Update(size)
+	m.stats, _ = m.stats.Update(size)
+	m.settings, _ = m.settings.Update(size)
+	return m, cmd
+}
+
+// handleTaskSelected makes the task the one being worked on. If the timer
+// hasn't started it's credited with the coming period, otherwise with the
+// next one.
+func handleTaskSelected(m Tomato, msg screens.TaskSelectedMsg) (tea.Model, tea.Cmd) {
+	m.task = msg.Name
+	m.tasks, _ = m.tasks.Update(msg)
+	m.tab = screens.TimerTab
+	if !m.timerStarted() {
+		m.currentView = m.viewForMode()
+	}
+	return m, nil
+}
+
+// handlePlanChanged credits the coming focus period to the current item in
+// the plan, if the timer hasn't started.
+func handlePlanChanged(m Tomato, msg screens.PlanChangedMsg) (tea.Model, tea.Cmd) {
+	if m.mode == focus && !m.timerStarted() {
+		m = m.followPlan()
+		m.currentView = m.viewForMode()
+	}
+	return m, nil
+}
+
+// handleLabelled sets the project and tags that focus periods are recorded
+// with, from the coming one if the timer hasn't started. Otherwise the period
+// under way keeps the label it started with, and the new one waits for the
+// next period.
+func handleLabelled(m Tomato, msg screens.LabelledMsg) (tea.Model, tea.Cmd) {
+	m.labelling = false
+	if msg.Cancelled {
+		return m, nil
+	}
+	m.nextLabel = &msg
+	if !m.timerStarted() {
+		m = m.applyLabel()
+		m.currentView = m.viewForMode()
+	}
+	return m, nil
+}
+
+// applyLabel makes a label set while the timer was running the current one.
+func (m Tomato) applyLabel() Tomato {
+	if m.nextLabel != nil {
+		m.project = m.nextLabel.Project
+		m.tags = m.nextLabel.Tags
+		m.nextLabel = nil
+	}
+	return m
+}
+
+// handleSettingChanged applies an edited setting from the next period onward,
+// and saves it to the config file.
+func handleSettingChanged(m Tomato, msg screens.SettingChangedMsg) (tea.Model, tea.Cmd) {
+	m = m.applySetting(msg.Key, msg.Value)
+	path, value := m.configSetting(msg.Key)
+	err := config.Set(m.configPath, path, value)
+	m.settings = m.settings.SetSettings(m.settingsRows()).Saved(err)
+	return m, nil
+}
+
+func (m Tomato) timerStarted() bool {
+	tv, ok := m.currentView.(timerview.TimerView)
+	return ok && tv.Started()
+}
+
+// showTabs reports whether there's room for the tab bar. The inline and mini
+// layouts only ever show the timer.
+func (m Tomato) showTabs() bool {
+	return !m.inline && m.layout != timerview.MiniLayout && m.currentHeight >= minTabsHeight
+}
+
+// visibleTab is the tab on screen, which is always the timer when there's no
+// room for the tab bar.
+func (m Tomato) visibleTab() screens.Tab {
+	if !m.showTabs() {
+		return screens.TimerTab
+	}
+	return m.tab
+}
+
+// screenHeight is the height left for the screens under the tab bar.
+func (m Tomato) screenHeight() int {
+	if m.showTabs() {
+		return m.currentHeight - screens.TabBarHeight
+	}
+	return m.currentHeight
+}
+
 func handleTimerComplete(m Tomato, msg timerview.TimerCompleteMsg) (tea.Model, tea.Cmd) {
+	outcome := history.Completed
+	if msg.Skipped {
+		outcome = history.Skipped
+	}
+	m, r, notify := m.record(msg.Period, outcome)
+
+	m.summary = m.periodSummary()
+	if m.once {
+		m.completed = true
+		m.periodEnded = true
+		return m, tea.Sequentially(notify, tea.Quit)
+	}
+
+	if m.reflect && r.IsFocus() && outcome == history.Completed && !m.inline {
+		m.reflecting = true
+		m.reflection = screens.NewReflection().SetKeys(screens.NewReflectionKeyMap(m.keys))
+		m.reflectionFor = r.Start
+	}
+
+	m = m.advance()
+	m.currentView = m.viewForMode()
+
+	if m.inline {
+		m.periodEnded = true
+		return m, tea.Sequentially(notify, tea.Quit)
+	}
+	return m, notify
+}
+
+// advance counts the period that just completed and moves on to the next
+// mode in the cycle.
+func (m Tomato) advance() Tomato {
 	if m.mode == focus {
 		m.tomatoCount++
+		m.todayCount = m.todayTomatos() + 1
+		m.today = currentDate()
 		if m.tomatoCount%m.longBreakTomatos == 0 {
 			m.mode = longBreak
 		} else {
 			m.mode = shortBreak
 		}
 	} else {
+		if m.mode == longBreak {
+			m.voidedCount = 0
+		}
 		m.mode = focus
+		m = m.followPlan()
 	}
+	return m.applyLabel()
+}
 
-	m.currentView = m.viewForMode()
+// followPlan makes the current item in the day's plan the task being worked
+// on. Without a plan, or once it's done, the task stays as it is.
+func (m Tomato) followPlan() Tomato {
+	p := m.plan.Plan()
+	if i := p.Current(); i >= 0 {
+		m.task = p.Items[i].Task
+	}
+	return m
+}
 
+// periodSummary is the line left in the scrollback when a period ends in
+// inline mode.
+func (m Tomato) periodSummary() string {
+	summary := fmt.Sprintf("%s  %s complete", time.Now().Format("15:04"), m.mode.Title())
+	if m.mode == focus {
+		summary = fmt.Sprintf("%s  🍅 #%d", summary, m.tomatoCount+1)
+	}
+	return summary
+}
+
+func handleTimerVoided(m Tomato, msg timerview.TimerVoidedMsg) (tea.Model, tea.Cmd) {
+	m, _, notify := m.record(msg.Period, history.Stopped)
+	if m.once {
+		return m, tea.Sequentially(notify, tea.Quit)
+	}
+	m = m.applyLabel()
+	if m.mode == focus {
+		m.voidedCount++
+		m.currentView = m.viewForMode()
+	}
+	return m, notify
+}
+
+// handleReflectionDone adds the note and rating to the focus period they're
+// about, and goes on to the break. A failure to save them is shown on the
+// stats screen, like other history errors.
+func handleReflectionDone(m Tomato, msg screens.ReflectionDoneMsg) (tea.Model, tea.Cmd) {
+	m.reflecting = false
+	if msg.Skipped {
+		return m, nil
+	}
+	if err := m.history.Annotate(m.reflectionFor, msg.Note, msg.Rating); err != nil {
+		m.stats = m.stats.SetError(fmt.Errorf("saving the reflection: %w", err))
+	}
+	m.stats = m.stats.Annotate(m.reflectionFor, msg.Note, msg.Rating)
 	return m, nil
 }
 
+// record adds the period that just ended to the history, and to the stats. It
+// returns a command sending any goal or budget notifications the period set off.
+func (m Tomato) record(period timerview.Period, outcome history.Outcome) (Tomato, history.Record, tea.Cmd) {
+	end := time.Now()
+	start := period.Started
+	if start.IsZero() {
+		start = end
+	}
+
+	r := history.Record{
+		Start:         start,
+		End:           end,
+		Phase:         m.mode.String(),
+		Task:          m.task,
+		Outcome:       outcome,
+		Interruptions: period.Interruptions,
+		Planned:       period.Planned,
+		Actual:        period.Elapsed,
+	}
+	if m.mode == focus {
+		r.Project = m.project
+		r.Tags = history.TagsFor(m.task, m.tags)
+	}
+	// Losing a record shouldn't stop the timer, so a failure to save it, or to
+	// write it to the journal, is shown on the stats screen instead.
+	if err := m.history.Append(r); err != nil {
+		m.stats = m.stats.SetError(fmt.Errorf("saving the history: %w", err))
+	}
+	if r.IsFocus() && outcome == history.Completed {
+		if err := m.journal.Write(r); err != nil {
+			m.stats = m.stats.SetError(fmt.Errorf("writing the journal: %w", err))
+		}
+		m.plan = m.plan.Credit(r.Task)
+	}
+	var budgetNotice, goalNotice tea.Cmd
+	before, hasBudget := m.budgets.For(r.Project, m.stats.Records(), end)
+	m.stats = m.stats.Add(r)
+	if after, _ := m.budgets.For(r.Project, m.stats.Records(), end); hasBudget && !before.Over() && after.Over() {
+		budgetNotice = m.notify(m.budgetNotification, notifications.Vars{
+			Project: after.Project,
+			Spent:   duration.FormatHours(after.Spent),
+			Budget:  duration.FormatHours(after.Budget),
+		})
+	}
+	if r.IsFocus() && outcome == history.Completed && m.goals.Daily > 0 {
+		if m.goals.Measure(m.stats.Records(), end).Today == m.goals.Daily {
+			goalNotice = m.notify(m.goalNotification, notifications.Vars{Today: m.goals.Daily, DailyGoal: m.goals.Daily})
+		}
+	}
+	if budgetNotice == nil && goalNotice == nil {
+		return m, r, nil
+	}
+	return m, r, tea.Sequentially(budgetNotice, goalNotice)
+}
+
+// projectLine shows the project and tags the focus period is recorded with,
+// and how the project is doing against its budget this week.
+func (m Tomato) projectLine() string {
+	if m.mode != focus {
+		return ""
+	}
+	line := screens.FormatLabel(m.project, m.tags)
+	if b, ok := m.budgets.For(m.project, m.stats.Records(), time.Now()); ok {
+		line = fmt.Sprintf("%s   %s", line, b)
+	}
+	return line
+}
+
+// goalsLine shows the progress towards the goals, if there are any.
+func (m Tomato) goalsLine() string {
+	if !m.goals.Set() {
+		return ""
+	}
+	return m.goals.Summary(m.goals.Measure(m.stats.Records(), time.Now()))
+}
+
+func currentDate() string {
+	return time.Now().Format("2006-01-02")
+}
+
+// todayTomatos is the number of tomatos completed today, which is counted
+// afresh once the date changes.
+func (m Tomato) todayTomatos() int {
+	if m.today != currentDate() {
+		return 0
+	}
+	return m.todayCount
+}
+
 func (m Tomato) viewForMode() View {
+	opts := m.modeOptions()
 	if m.mode == focus {
-		return timerview.NewFocusMode(m.focusTime, time.Second, m.currentWidth, m.currentHeight, m.noiseModeScript, m.quietModeScript)
+		return timerview.NewFocusMode(m.focusTime, time.Second, m.currentWidth, m.screenHeight(), opts)
 	} else if m.mode == shortBreak {
-		return timerview.NewBreakMode(m.shortBreakTime, time.Second, m.currentWidth, m.currentHeight)
+		return timerview.NewBreakMode(m.shortBreakTime, time.Second, m.currentWidth, m.screenHeight(), opts)
+	} else {
+		return timerview.NewBreakMode(m.longBreakTime, time.Second, m.currentWidth, m.screenHeight(), opts)
+	}
+}
+
+func (m Tomato) modeOptions() timerview.ModeOptions {
+	tmpl, vars := m.notificationForMode()
+	title, body, err := tmpl.Render(vars)
+	if err != nil {
+		title, body = tmpl.Title, tmpl.Body
+	}
+
+	return timerview.ModeOptions{
+		NoiseModeScript:   m.noiseModeScript,
+		QuietModeScript:   m.quietModeScript,
+		NotificationTitle: title,
+		NotificationBody:  body,
+		Notifier:          m.notifier,
+		Warnings:          m.warningsForMode(vars),
+		Phase:             m.mode.Title(),
+		Reporter:          m.reporter,
+		Font:              m.font,
+		Cycle:             m.cycleForMode(),
+		Task:              m.taskLine(),
+		Project:           m.projectLine(),
+		Goals:             m.goalsLine(),
+		Forecast:          m.forecast,
+		Colors:            m.colorsForMode(),
+		Keys:              m.keys,
+		Layout:            m.layout,
+	}
+}
+
+func (m Tomato) colorsForMode() theme.Phase {
+	if m.mode == focus {
+		return m.theme.Focus
+	}
+	return m.theme.Break
+}
+
+// forecast describes how many more tomatos fit before the next boundary, if
+// the current period ends at end.
+func (m Tomato) forecast(end time.Time) string {
+	until, ok := forecast.NextBoundary(end, m.boundaries)
+	if !ok {
+		return ""
+	}
+
+	count := m.cyclePlan().Tomatoes(m.mode == focus, end, m.tomatoCount, until)
+	return fmt.Sprintf("%d more before %s", count, until.Format("15:04"))
+}
+
+func (m Tomato) cyclePlan() forecast.Plan {
+	return forecast.Plan{
+		Focus:            m.focusTime,
+		ShortBreak:       m.shortBreakTime,
+		LongBreak:        m.longBreakTime,
+		LongBreakTomatos: m.longBreakTomatos,
+	}
+}
+
+// available is the focus time left between now, or the start of the working
+// day if that's later, and the end of the working day.
+func (m Tomato) available() screens.Availability {
+	a := screens.Availability{Focus: m.focusTime}
+	if m.dayEnd == 0 {
+		return a
+	}
+
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := now
+	if start := midnight.Add(m.dayStart); start.After(from) {
+		from = start
+	}
+	a.Until = midnight.Add(m.dayEnd)
+	a.Tomatos = m.cyclePlan().Tomatoes(false, from, m.tomatoCount, a.Until)
+	return a
+}
+
+// taskLine names the task that the focus period is credited to, along with
+// what's left of its estimate when it's in the plan.
+func (m Tomato) taskLine() string {
+	if m.mode != focus || m.task == "" {
+		return ""
+	}
+	for _, item := range m.plan.Plan().Items {
+		if left := item.Left(); item.Task == m.task && left > 0 {
+			return fmt.Sprintf("%s   %d of %d 🍅 left", m.task, left, item.Estimate)
+		}
+	}
+	return m.task
+}
+
+func (m Tomato) cycleForMode() timerview.Cycle {
+	cycle := timerview.Cycle{
+		Completed: m.tomatoCount % m.longBreakTomatos,
+		Voided:    m.voidedCount,
+		Length:    m.longBreakTomatos,
+		Today:     m.todayTomatos(),
+		NextPhase: focus.Title(),
+	}
+
+	switch m.mode {
+	case longBreak:
+		cycle.Completed = m.longBreakTomatos
+	case focus:
+		if (m.tomatoCount+1)%m.longBreakTomatos == 0 {
+			cycle.NextPhase = longBreak.Title()
+		} else {
+			cycle.NextPhase = shortBreak.Title()
+		}
+	}
+	return cycle
+}
+
+func (m Tomato) warningsForMode(vars notifications.Vars) []timerview.Warning {
+	warnings := []timerview.Warning{}
+	for _, w := range m.warnings {
+		if !w.appliesTo(m.mode) {
+			continue
+		}
+
+		vars.Remaining = w.before.String()
+		title, body, err := w.template.Render(vars)
+		if err != nil {
+			title, body = w.template.Title, w.template.Body
+		}
+		warnings = append(warnings, timerview.Warning{
+			Before: w.before,
+			Title:  title,
+			Body:   body,
+			Hook:   w.hook,
+		})
+	}
+	return warnings
+}
+
+// notify returns a command sending a notification that isn't tied to the end
+// of a period.
+func (m Tomato) notify(tmpl notifications.Template, vars notifications.Vars) tea.Cmd {
+	title, body, err := tmpl.Render(vars)
+	if err != nil {
+		title, body = tmpl.Title, tmpl.Body
+	}
+	notifier := m.notifier
+	return func() tea.Msg {
+		notifier.Notify(title, body)
+		return nil
+	}
+}
+
+// notificationForMode picks the template for the current mode, along with the
+// values it will be rendered with when the period ends.
+func (m Tomato) notificationForMode() (notifications.Template, notifications.Vars) {
+	vars := notifications.Vars{
+		Phase:         m.mode.String(),
+		TomatoCount:   m.tomatoCount,
+		CyclePosition: m.tomatoCount % m.longBreakTomatos,
+		CycleLength:   m.longBreakTomatos,
+		Task:          m.task,
+		NextPhase:     focus.String(),
+		NextDuration:  duration.Format(m.focusTime),
+	}
+
+	switch m.mode {
+	case shortBreak:
+		return m.shortBreakNotification, vars
+	case longBreak:
+		return m.longBreakNotification, vars
+	}
+
+	vars.TomatoCount++
+	vars.CyclePosition++
+	if vars.TomatoCount%m.longBreakTomatos == 0 {
+		vars.NextPhase = longBreak.String()
+		vars.NextDuration = duration.Format(m.longBreakTime)
 	} else {
-		return timerview.NewBreakMode(m.longBreakTime, time.Second, m.currentWidth, m.currentHeight)
+		vars.NextPhase = shortBreak.String()
+		vars.NextDuration = duration.Format(m.shortBreakTime)
 	}
+	return m.focusNotification, vars
 }
 
 func (m Tomato) View() string {
-	return m.currentView.View()
+	if m.periodEnded {
+		// Clear the live timer, so that the summary can take its place.
+		return ""
+	}
+	if !m.showTabs() {
+		if m.reflecting {
+			return m.reflection.View()
+		}
+		if m.labelling {
+			return m.label.View()
+		}
+		return m.currentView.View()
+	}
+
+	screen := m.tabView()
+	if m.reflecting {
+		screen = m.reflection.View()
+	} else if m.labelling {
+		screen = m.label.View()
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, screens.TabBar(m.tab, m.currentWidth, m.colorsForMode()), screen)
+}
+
+// tabView is the screen for the active tab.
+func (m Tomato) tabView() string {
+	switch m.tab {
+	case screens.PlanTab:
+		return m.plan.SetAvailable(m.available()).View()
+	case screens.TasksTab:
+		return m.tasks.View()
+	case screens.StatsTab:
+		return m.stats.View()
+	case screens.SettingsTab:
+		return m.settings.View()
+	default:
+		return m.currentView.View()
+	}
 }
 
 func main() {
-	var focusTimeFlag = flag.String("f", "25m", "Sets the length of the focus period, expressed in <number><unit> eg 25m")
-	var shortBreakTimeFlag = flag.String("s", "5m", "Sets the length of the short break, expressed in <number><unit> eg 5m")
-	var longBreakTimeFlag = flag.String("l", "15m", "Sets the length of the long break, expressed in <number><unit> eg 15m")
-	var longBreakTomatosFlag = flag.Int("L", 4, "Sets the number of tomatos per long break, expressed in <number> eg 4")
-	var quietModeFlag = flag.String("q", "tomato_quiet.sh", "Sets the script to run when focus mode starts")
-	var noiseModeFlag = flag.String("n", "tomato_noise.sh", "Sets the script to run when focus mode ends")
-
-	flag.Parse()
-	m := Tomato{
-		currentView:      timerview.NewFocusMode(*focusTimeFlag, time.Second, 120, 40, *noiseModeFlag, *quietModeFlag),
-		mode:             focus,
-		tomatoCount:      0,
-		currentWidth:     120,
-		currentHeight:    40,
-		focusTime:        *focusTimeFlag,
-		shortBreakTime:   *shortBreakTimeFlag,
-		longBreakTime:    *longBreakTimeFlag,
-		longBreakTomatos: *longBreakTomatosFlag,
-		quietModeScript:  *quietModeFlag,
-		noiseModeScript:  *noiseModeFlag,
-	}
-
-	if err := tea.NewProgram(m, tea.WithAltScreen()).Start(); err != nil {
-		fmt.Println("Error running program:", err)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "once":
+			os.Exit(runOnce(os.Args[2:]))
+		case "export":
+			os.Exit(runExport(os.Args[2:], os.Stdout, os.Stderr))
+		case "status":
+			os.Exit(runStatus(os.Args[2:], os.Stdout, os.Stderr))
+		case "report":
+			os.Exit(runReport(os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
+
+	opts := parseOptions("tomato", os.Args[1:])
+	m, err := newTomato(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error", err)
+		os.Exit(1)
+	}
+
+	if opts.plain || !isTerminal(os.Stdout) {
+		os.Exit(runPlain(m, os.Stdout, notifySignals()))
+	}
+
+	m.reporter.Save()
+	if m.inline {
+		err = runInline(m)
+	} else {
+		_, err = startProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	}
+	m.reporter.Restore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error running program:", err)
 		os.Exit(1)
 	}
 }
+
+// runOnce runs a single focus period, and returns the exit code: 0 if the
+// period completed, or non-zero if it was stopped.
+func runOnce(args []string) int {
+	opts := parseOptions("once", args)
+	m, err := newTomato(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error", err)
+		return exitError
+	}
+	m.once = true
+
+	if opts.plain || !isTerminal(os.Stdout) {
+		_, completed, sig := runPlainPeriod(plainNotifier(m), os.Stdout, notifySignals())
+		if !completed {
+			return exitCode(sig)
+		}
+		return 0
+	}
+
+	options := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
+	if m.inline {
+		options = nil
+	}
+	m.reporter.Save()
+	final, err := startProgram(m, options...)
+	m.reporter.Restore()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error running program:", err)
+		return exitError
+	}
+
+	m = final.(Tomato)
+	if !m.completed {
+		return exitStopped
+	}
+	if m.inline {
+		fmt.Println(m.summary)
+	}
+	return 0
+}
+
+// startProgram runs m as a program drawing to the model's output, so that the
+// escape sequences its commands send are kept out of the frames being drawn.
+func startProgram(m Tomato, options ...tea.ProgramOption) (tea.Model, error) {
+	p := tea.NewProgram(m, append(options, tea.WithOutput(m.output))...)
+	done := make(chan struct{})
+	defer close(done)
+	go m.output.Attach(done, func(width int, height int) {
+		p.Send(tea.WindowSizeMsg{Width: width, Height: height})
+	})
+	return p.StartReturningModel()
+}
+
+// runInline runs each period as its own program, outside the alternate
+// screen, printing a summary of each period as it ends.
+func runInline(m Tomato) error {
+	for {
+		final, err := startProgram(m)
+		if err != nil {
+			return err
+		}
+
+		m = final.(Tomato)
+		if !m.periodEnded {
+			return nil
+		}
+		fmt.Println(m.summary)
+		m.periodEnded = false
+	}
+}
@@ -1,9 +1,23 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/guysherman/tomato/config"
+	"github.com/guysherman/tomato/goals"
+	"github.com/guysherman/tomato/history"
+	"github.com/guysherman/tomato/journal"
+	"github.com/guysherman/tomato/notifications"
+	"github.com/guysherman/tomato/plan"
+	"github.com/guysherman/tomato/screens"
+	"github.com/guysherman/tomato/tasks"
 	"github.com/guysherman/tomato/timerview"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -21,6 +35,597 @@ func TestMain(t *testing.T) {
 			Convey("cmd is nil", func() {
 				So(cmd, ShouldBeNil)
 			})
+
+			Convey("tomato is counted towards the cycle", func() {
+				m := t.(Tomato)
+				So(m.mode, ShouldEqual, shortBreak)
+				So(m.cycleForMode(), ShouldResemble, timerview.Cycle{
+					Completed: 1,
+					Length:    4,
+					Today:     1,
+					NextPhase: "Focus",
+				})
+			})
+		})
+
+		Convey("FocusCompleteMsg in inline mode ends the program", func() {
+			var t tea.Model
+			t = Tomato{
+				longBreakTomatos: 4,
+				inline:           true,
+			}
+			t, cmd := t.Update(timerview.TimerCompleteMsg{})
+
+			m := t.(Tomato)
+			So(fmt.Sprintf("%T", cmd()), ShouldEqual, fmt.Sprintf("%T", tea.Quit()))
+			So(m.periodEnded, ShouldBeTrue)
+			So(m.summary, ShouldEndWith, "Focus complete  🍅 #1")
+			So(m.View(), ShouldEqual, "")
+		})
+
+		Convey("TimerVoidedMsg in focus mode is counted towards the cycle", func() {
+			var t tea.Model
+			t = Tomato{
+				longBreakTomatos: 4,
+			}
+			t, _ = t.Update(timerview.TimerVoidedMsg{})
+
+			m := t.(Tomato)
+			So(m.voidedCount, ShouldEqual, 1)
+			So(m.cycleForMode().Voided, ShouldEqual, 1)
+			So(m.cycleForMode().NextPhase, ShouldEqual, "Short Break")
+		})
+
+		Convey("Once mode", func() {
+			var t tea.Model
+			t = Tomato{
+				longBreakTomatos: 4,
+				once:             true,
+			}
+
+			Convey("starts the timer straight away", func() {
+				So(startTimer(), ShouldResemble, timerview.StartMsg{})
+				So(t.Init(), ShouldNotBeNil)
+			})
+
+			Convey("ends the program when the period completes", func() {
+				t, cmd := t.Update(timerview.TimerCompleteMsg{})
+
+				m := t.(Tomato)
+				So(fmt.Sprintf("%T", cmd()), ShouldEqual, fmt.Sprintf("%T", tea.Quit()))
+				So(m.completed, ShouldBeTrue)
+				So(m.mode, ShouldEqual, focus)
+			})
+
+			Convey("ends the program, incomplete, when the period is stopped", func() {
+				t, cmd := t.Update(timerview.TimerVoidedMsg{})
+
+				m := t.(Tomato)
+				So(fmt.Sprintf("%T", cmd()), ShouldEqual, fmt.Sprintf("%T", tea.Quit()))
+				So(m.completed, ShouldBeFalse)
+			})
+		})
+
+		Convey("Tabs", func() {
+			m := Tomato{
+				longBreakTomatos: 4,
+				focusTime:        25 * time.Minute,
+				currentWidth:     120,
+				currentHeight:    40,
+				keys:             timerview.DefaultKeyMap(),
+				tasks:            screens.NewTasks(tasks.Store{}, []tasks.Task{{Name: "Refactor parser"}}, ""),
+			}
+			m.currentView = m.viewForMode()
+			var t tea.Model = m
+
+			Convey("tab moves to the next screen", func() {
+				t, _ = t.Update(tea.KeyMsg{Type: tea.KeyTab})
+				So(t.(Tomato).tab, ShouldEqual, screens.PlanTab)
+				t, _ = t.Update(tea.KeyMsg{Type: tea.KeyTab})
+				So(t.(Tomato).tab, ShouldEqual, screens.TasksTab)
+				So(t.View(), ShouldContainSubstring, "Refactor parser")
+
+				t, _ = t.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+				So(t.(Tomato).tab, ShouldEqual, screens.PlanTab)
+			})
+
+			Convey("the timer keeps ticking on another tab", func() {
+				t, cmd := t.Update(timerview.StartMsg{})
+				tick := cmd()
+				t, _ = t.Update(tea.KeyMsg{Type: tea.KeyTab})
+				t, cmd = t.Update(tick)
+
+				So(t.(Tomato).tab, ShouldEqual, screens.PlanTab)
+				So(t.(Tomato).timerStarted(), ShouldBeTrue)
+				So(cmd, ShouldNotBeNil)
+			})
+
+			Convey("picking a task credits the timer with it", func() {
+				t, _ = t.Update(tea.KeyMsg{Type: tea.KeyTab})
+				t, _ = t.Update(tea.KeyMsg{Type: tea.KeyTab})
+				t, cmd := t.Update(tea.KeyMsg{Type: tea.KeyEnter})
+				t, _ = t.Update(cmd())
+
+				So(t.(Tomato).task, ShouldEqual, "Refactor parser")
+				So(t.(Tomato).tab, ShouldEqual, screens.TimerTab)
+			})
+
+			Convey("a small window only shows the timer", func() {
+				t, _ = t.Update(tea.WindowSizeMsg{Width: 40, Height: 8})
+				t, _ = t.Update(tea.KeyMsg{Type: tea.KeyTab})
+				So(t.(Tomato).visibleTab(), ShouldEqual, screens.TimerTab)
+				So(t.View(), ShouldNotContainSubstring, "Settings")
+			})
+		})
+
+		Convey("Completed and stopped periods are recorded", func() {
+			store := history.Store{Path: filepath.Join(t.TempDir(), "history.jsonl")}
+			var t tea.Model = Tomato{
+				longBreakTomatos: 4,
+				task:             "Refactor parser",
+				history:          store,
+			}
+			t, _ = t.Update(timerview.TimerCompleteMsg{Period: timerview.Period{Planned: 25 * time.Minute, Elapsed: 25 * time.Minute, Interruptions: 2}})
+			t, _ = t.Update(timerview.TimerCompleteMsg{Skipped: true})
+			t, _ = t.Update(timerview.TimerVoidedMsg{})
+
+			records, err := store.Load()
+			So(err, ShouldBeNil)
+			So(records, ShouldHaveLength, 3)
+			So(records[0].Phase, ShouldEqual, "focus")
+			So(records[0].Task, ShouldEqual, "Refactor parser")
+			So(records[0].Outcome, ShouldEqual, history.Completed)
+			So(records[0].Interruptions, ShouldEqual, 2)
+			So(records[0].Actual, ShouldEqual, 25*time.Minute)
+			So(records[1].Phase, ShouldEqual, "short break")
+			So(records[1].Outcome, ShouldEqual, history.Skipped)
+			So(records[2].Outcome, ShouldEqual, history.Stopped)
+		})
+
+		Convey("Reflections are saved with the focus period", func() {
+			store := history.Store{Path: filepath.Join(t.TempDir(), "history.jsonl")}
+			var t tea.Model = Tomato{
+				longBreakTomatos: 4,
+				history:          store,
+				reflect:          true,
+			}
+			start := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+			t, _ = t.Update(timerview.TimerCompleteMsg{Period: timerview.Period{Started: start}})
+			So(t.(Tomato).reflecting, ShouldBeTrue)
+			So(t.(Tomato).mode, ShouldEqual, shortBreak)
+
+			t, _ = t.Update(screens.ReflectionDoneMsg{Note: "Split the lexer out", Rating: 4})
+			So(t.(Tomato).reflecting, ShouldBeFalse)
+
+			records, err := store.Load()
+			So(err, ShouldBeNil)
+			So(records[0].Note, ShouldEqual, "Split the lexer out")
+			So(records[0].Rating, ShouldEqual, 4)
+		})
+
+		Convey("While reflecting", func() {
+			store := history.Store{Path: filepath.Join(t.TempDir(), "history.jsonl")}
+			m := Tomato{
+				longBreakTomatos: 4,
+				currentWidth:     60,
+				currentHeight:    20,
+				history:          store,
+				reflect:          true,
+			}
+			m.currentView = m.viewForMode()
+			var t tea.Model = m
+			t, _ = t.Update(timerview.TimerCompleteMsg{Period: timerview.Period{Started: time.Now()}})
+
+			Convey("clicks don't reach the timer", func() {
+				for y := 0; y < 20; y++ {
+					for x := 0; x < 60; x++ {
+						t, _ = t.Update(tea.MouseMsg{Type: tea.MouseLeft, X: x, Y: y})
+					}
+				}
+				So(t.(Tomato).timerStarted(), ShouldBeFalse)
+			})
+
+			Convey("a failure to save is shown on the stats screen", func() {
+				os.Remove(store.Path)
+				t, _ = t.Update(screens.ReflectionDoneMsg{Note: "Split the lexer out"})
+				So(t.(Tomato).stats.View(), ShouldContainSubstring, "Error: saving the reflection")
+			})
+		})
+
+		Convey("The plan picks the task for each focus period", func() {
+			m := Tomato{
+				longBreakTomatos: 4,
+				focusTime:        25 * time.Minute,
+				currentWidth:     120,
+				currentHeight:    40,
+				plan: screens.NewPlan(plan.Store{}, plan.Plan{Items: []plan.Item{
+					{Task: "Refactor parser", Estimate: 1},
+					{Task: "Write tests", Estimate: 2},
+				}}),
+			}
+			m = m.followPlan()
+			So(m.task, ShouldEqual, "Refactor parser")
+
+			var t tea.Model = m
+			t, _ = t.Update(timerview.TimerCompleteMsg{})
+			t, _ = t.Update(timerview.TimerCompleteMsg{})
+
+			m = t.(Tomato)
+			So(m.plan.Plan().Items[0].Spent, ShouldEqual, 1)
+			So(m.task, ShouldEqual, "Write tests")
+			So(m.currentView.View(), ShouldContainSubstring, "Write tests   2 of 2 🍅 left")
+		})
+
+		Convey("Focus periods are recorded with the project and tags", func() {
+			store := history.Store{Path: filepath.Join(t.TempDir(), "history.jsonl")}
+			m := Tomato{
+				longBreakTomatos: 4,
+				focusTime:        25 * time.Minute,
+				currentWidth:     120,
+				currentHeight:    40,
+				task:             "Fix login #bug",
+				keys:             timerview.DefaultKeyMap(),
+				history:          store,
+				budgets:          goals.Budgets{"acme": 10 * time.Hour},
+			}
+			m.currentView = m.viewForMode()
+			var t tea.Model = m
+
+			t, _ = t.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+			So(t.View(), ShouldContainSubstring, "Which project")
+			for _, r := range "acme #review" {
+				t, _ = t.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+			}
+			t, cmd := t.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			t, _ = t.Update(cmd())
+			So(t.View(), ShouldContainSubstring, "acme #review   acme 0h00m of 10h00m this week")
+
+			t, _ = t.Update(timerview.TimerCompleteMsg{})
+			records, err := store.Load()
+			So(err, ShouldBeNil)
+			So(records[0].Project, ShouldEqual, "acme")
+			So(records[0].Tags, ShouldResemble, []string{"bug", "review"})
+
+			Convey("but breaks aren't", func() {
+				t, _ = t.Update(timerview.TimerCompleteMsg{})
+				records, _ := store.Load()
+				So(records[1].Phase, ShouldEqual, "short break")
+				So(records[1].Project, ShouldBeEmpty)
+				So(records[1].Tags, ShouldBeEmpty)
+			})
+
+			Convey("and a label set while the timer runs waits for the next period", func() {
+				t, _ = t.Update(timerview.TimerCompleteMsg{})
+				t, _ = t.Update(tea.KeyMsg{Type: tea.KeySpace})
+				So(t.(Tomato).timerStarted(), ShouldBeTrue)
+
+				t, _ = t.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+				t, _ = t.Update(screens.LabelledMsg{Project: "globex"})
+				t, _ = t.Update(timerview.TimerCompleteMsg{})
+				records, _ := store.Load()
+				So(records[2].Project, ShouldEqual, "acme")
+				So(t.(Tomato).project, ShouldEqual, "globex")
+			})
+		})
+
+		Convey("Tags can be given as a list", func() {
+			So(splitTags("review, #urgent,,"), ShouldResemble, []string{"review", "urgent"})
+		})
+
+		Convey("Settings", func() {
+			path := filepath.Join(t.TempDir(), "config.json")
+			m := Tomato{
+				longBreakTomatos: 4,
+				focusTime:        25 * time.Minute,
+				config:           config.Default(),
+				configPath:       path,
+			}
+
+			Convey("edits apply to the next period and are saved", func() {
+				model, _ := m.Update(screens.SettingChangedMsg{Key: "focus", Value: "45m"})
+				model, _ = model.Update(screens.SettingChangedMsg{Key: "long_break_tomatos", Value: "3"})
+
+				m := model.(Tomato)
+				So(m.focusTime, ShouldEqual, 45*time.Minute)
+				So(m.longBreakTomatos, ShouldEqual, 3)
+
+				saved, err := config.Load(path)
+				So(err, ShouldBeNil)
+				So(saved.Timer.Focus, ShouldEqual, "45m")
+				So(saved.Timer.LongBreakTomatos, ShouldEqual, 3)
+
+				data, _ := os.ReadFile(path)
+				So(string(data), ShouldNotContainSubstring, "short_break")
+				So(string(data), ShouldNotContainSubstring, "journal")
+			})
+
+			Convey("are checked like the flags", func() {
+				So(m.checkLength(focus)("5m"), ShouldBeNil)
+				So(m.checkLength(focus)("5"), ShouldBeNil)
+				So(m.checkLength(focus)("five"), ShouldNotBeNil)
+				So(m.checkLength(focus)("-5m"), ShouldNotBeNil)
+				So(checkTomatos("0"), ShouldNotBeNil)
+				So(checkBackend("notify-send"), ShouldBeNil)
+				So(m.checkTheme("nope"), ShouldNotBeNil)
+			})
+
+			Convey("lengths must leave room for the warnings", func() {
+				m.config.Warnings = []config.Warning{{Phase: "focus", Before: "2m", Title: "Nearly"}}
+				So(m.checkLength(focus)("3m"), ShouldBeNil)
+				err := m.checkLength(focus)("1m")
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "focus length of 1m")
+				So(m.settingsRows()[0].Validate("2m"), ShouldNotBeNil)
+			})
+
+			Convey("flags take precedence over the config", func() {
+				opts := options{focusTime: "10m", set: map[string]bool{"f": true}}
+				timer := opts.timerConfig(config.Default().Timer)
+				So(timer.Focus, ShouldEqual, "10m")
+				So(timer.ShortBreak, ShouldEqual, "5m")
+			})
+		})
+
+		Convey("Completed focus periods are written to the journal", func() {
+			dir := t.TempDir()
+			notes, _ := journal.New(journal.Markdown, filepath.Join(dir, "journal.md"))
+			var t tea.Model = Tomato{
+				longBreakTomatos: 4,
+				task:             "Refactor parser",
+				journal:          notes,
+			}
+			t, _ = t.Update(timerview.TimerCompleteMsg{Period: timerview.Period{Interruptions: 2}})
+			t, _ = t.Update(timerview.TimerCompleteMsg{})
+			t, _ = t.Update(timerview.TimerVoidedMsg{})
+
+			data, err := os.ReadFile(filepath.Join(dir, "journal.md"))
+			So(err, ShouldBeNil)
+			So(strings.Count(string(data), "\n"), ShouldEqual, 1)
+			So(string(data), ShouldEndWith, "🍅 Refactor parser (2 interruptions)\n")
+		})
+
+		Convey("Today's tomatos are counted from the history at startup", func() {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, "config.json")
+			config.Set(configPath, "data_dir", dir)
+			historyStore(dir).Append(history.Record{Start: time.Now(), Phase: "focus", Outcome: history.Completed})
+
+			m, err := newTomato(parseOptions("tomato", []string{"-c", configPath}))
+			So(err, ShouldBeNil)
+			So(m.cycleForMode().Today, ShouldEqual, 1)
+		})
+
+		Convey("Warnings must end before the periods they apply to", func() {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, "config.json")
+			config.Set(configPath, "data_dir", dir)
+			config.Set(configPath, "warnings", []config.Warning{{Phase: "break", Before: "5m", Title: "Nearly"}})
+
+			_, err := newTomato(parseOptions("tomato", []string{"-c", configPath}))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "short break length of 5m")
+
+			_, err = newTomato(parseOptions("tomato", []string{"-c", configPath, "-s", "6m"}))
+			So(err, ShouldBeNil)
+		})
+
+		Convey("A failure to save the history is shown on the stats screen", func() {
+			var t tea.Model = Tomato{
+				longBreakTomatos: 4,
+				history:          history.Store{Path: t.TempDir()},
+			}
+			t, _ = t.Update(timerview.TimerCompleteMsg{})
+			So(t.(Tomato).stats.View(), ShouldContainSubstring, "Error: saving the history")
+		})
+
+		Convey("Export", func() {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, "config.json")
+			config.Set(configPath, "data_dir", dir)
+
+			store := historyStore(dir)
+			store.Append(history.Record{Start: time.Date(2026, 10, 18, 10, 0, 0, 0, time.Local), Phase: "focus", Task: "Sunday"})
+			store.Append(history.Record{Start: time.Date(2026, 10, 19, 10, 0, 0, 0, time.Local), Phase: "focus", Task: "Monday"})
+			store.Append(history.Record{Start: time.Date(2026, 10, 20, 10, 0, 0, 0, time.Local), Phase: "focus", Task: "Tuesday"})
+
+			Convey("writes the periods in the range, inclusive", func() {
+				out := &bytes.Buffer{}
+				code := runExport([]string{"-c", configPath, "-since", "2026-10-19", "-until", "2026-10-20"}, out, &bytes.Buffer{})
+				So(code, ShouldEqual, 0)
+				So(out.String(), ShouldNotContainSubstring, "Sunday")
+				So(out.String(), ShouldContainSubstring, "Monday")
+				So(out.String(), ShouldContainSubstring, "Tuesday")
+			})
+
+			Convey("writes to a file", func() {
+				path := filepath.Join(dir, "tomato.ics")
+				code := runExport([]string{"-c", configPath, "-format", "ics", "-o", path}, &bytes.Buffer{}, &bytes.Buffer{})
+				So(code, ShouldEqual, 0)
+				data, _ := os.ReadFile(path)
+				So(strings.Count(string(data), "BEGIN:VEVENT"), ShouldEqual, 3)
+			})
+
+			Convey("rejects bad dates and formats", func() {
+				stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+				So(runExport([]string{"-c", configPath, "-since", "last week"}, stdout, stderr), ShouldEqual, exitError)
+				So(stderr.String(), ShouldStartWith, "Error reading -since")
+				So(runExport([]string{"-c", configPath, "-format", "xml"}, stdout, stderr), ShouldEqual, exitError)
+				So(stdout.String(), ShouldBeEmpty)
+
+				stderr.Reset()
+				So(runExport([]string{"-c", configPath, "-o", filepath.Join(dir, "missing", "tomato.csv")}, stdout, stderr), ShouldEqual, exitError)
+				So(stderr.String(), ShouldStartWith, "Error writing export")
+			})
+		})
+
+		Convey("Report", func() {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, "config.json")
+			config.Set(configPath, "data_dir", dir)
+			config.Set(configPath, "report.minimum", "30m")
+
+			monday := time.Date(2026, 10, 12, 9, 0, 0, 0, time.Local)
+			store := historyStore(dir)
+			store.Append(history.Record{Start: monday, Phase: "focus", Project: "acme", Outcome: history.Completed, Actual: 25 * time.Minute})
+			store.Append(history.Record{Start: monday.Add(time.Hour), Phase: "focus", Project: "acme", Outcome: history.Completed, Actual: 28 * time.Minute})
+			store.Append(history.Record{Start: monday.AddDate(0, 0, 1), Phase: "focus", Project: "globex", Outcome: history.Completed, Actual: 10 * time.Minute})
+			store.Append(history.Record{Start: monday.AddDate(0, 0, 1), Phase: "focus", Project: "globex", Outcome: history.Stopped, Actual: 20 * time.Minute})
+
+			Convey("prints hours per project per day, with the config's minimum", func() {
+				out := &bytes.Buffer{}
+				code := runReport([]string{"-c", configPath, "-week", "2026-W42", "-round", "15m"}, out, &bytes.Buffer{})
+				So(code, ShouldEqual, 0)
+				So(out.String(), ShouldStartWith, "Week 2026-W42, Mon 12 Oct to Sun 18 Oct, rounded up to 15m, minimum 30m\n")
+				So(out.String(), ShouldContainSubstring, "acme       1.00       -")
+				So(out.String(), ShouldContainSubstring, "globex        -    0.50")
+			})
+
+			Convey("writes CSV", func() {
+				out := &bytes.Buffer{}
+				code := runReport([]string{"-c", configPath, "-week", "2026-W42", "-format", "csv", "-min", "1m"}, out, &bytes.Buffer{})
+				So(code, ShouldEqual, 0)
+				So(out.String(), ShouldContainSubstring, "globex,0.00,0.17,0.00")
+			})
+
+			Convey("turns off the config's minimum with -min 0", func() {
+				out := &bytes.Buffer{}
+				code := runReport([]string{"-c", configPath, "-week", "2026-W42", "-format", "csv", "-min", "0"}, out, &bytes.Buffer{})
+				So(code, ShouldEqual, 0)
+				So(out.String(), ShouldContainSubstring, "globex,0.00,0.17,0.00")
+			})
+
+			Convey("rejects bad weeks, groupings and rules", func() {
+				stderr := &bytes.Buffer{}
+				So(runReport([]string{"-c", configPath, "-week", "42"}, &bytes.Buffer{}, stderr), ShouldEqual, exitError)
+				So(stderr.String(), ShouldStartWith, "Error reading -week")
+				So(runReport([]string{"-c", configPath, "-by", "client"}, &bytes.Buffer{}, &bytes.Buffer{}), ShouldEqual, exitError)
+				So(runReport([]string{"-c", configPath, "-rounding", "sideways"}, &bytes.Buffer{}, &bytes.Buffer{}), ShouldEqual, exitError)
+				_, err := reportRules(config.Report{Round: "quarter"}, "", "", "")
+				So(err.Error(), ShouldStartWith, "reading report.round in the config file")
+			})
+		})
+
+		Convey("Status shows progress towards the goals", func() {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, "config.json")
+			config.Set(configPath, "data_dir", dir)
+			config.Set(configPath, "goals", config.Goals{DailyTomatos: 4, WeeklyHours: 10})
+
+			now := time.Now()
+			store := historyStore(dir)
+			store.Append(history.Record{Start: now.AddDate(0, 0, -1), Phase: "focus", Outcome: history.Completed, Actual: 25 * time.Minute})
+			store.Append(history.Record{Start: now, Phase: "focus", Outcome: history.Completed, Actual: 25 * time.Minute})
+
+			out := &bytes.Buffer{}
+			So(runStatus([]string{"-c", configPath}, out, &bytes.Buffer{}), ShouldEqual, 0)
+			So(out.String(), ShouldContainSubstring, "Today      1 of 4 🍅")
+			So(out.String(), ShouldContainSubstring, "of 10h00m focused")
+
+			out.Reset()
+			So(runStatus([]string{"-c", configPath, "-short"}, out, &bytes.Buffer{}), ShouldEqual, 0)
+			So(out.String(), ShouldStartWith, "1/4 🍅 today   ")
+		})
+
+		Convey("Short status without goals or a streak shows today's count", func() {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, "config.json")
+			config.Set(configPath, "data_dir", dir)
+
+			out := &bytes.Buffer{}
+			So(runStatus([]string{"-c", configPath, "-short"}, out, &bytes.Buffer{}), ShouldEqual, 0)
+			So(out.String(), ShouldEqual, "0 🍅 today\n")
+		})
+
+		Convey("The timer shows progress towards the goals", func() {
+			m := Tomato{
+				longBreakTomatos: 4,
+				focusTime:        25 * time.Minute,
+				currentWidth:     120,
+				currentHeight:    40,
+				goals:            goals.Goals{Daily: 2},
+			}
+			var t tea.Model = m
+			t, _ = t.Update(timerview.TimerCompleteMsg{Period: timerview.Period{Started: time.Now(), Elapsed: 25 * time.Minute}})
+			So(t.(Tomato).currentView.View(), ShouldContainSubstring, "1/2 🍅 today")
+		})
+
+		Convey("Reaching the daily goal sends the goal notification", func() {
+			sent := ""
+			goal, _ := newTemplate(config.Default().Notifications.Goal)
+			var t tea.Model = Tomato{
+				longBreakTomatos: 4,
+				goals:            goals.Goals{Daily: 1},
+				goalNotification: goal,
+				notifier:         notifications.Notifier{Output: func(s string) { sent += s }},
+			}
+			t, cmd := t.Update(timerview.TimerCompleteMsg{Period: timerview.Period{Started: time.Now()}})
+			So(sent, ShouldBeEmpty)
+			cmd()
+			So(sent, ShouldContainSubstring, "Daily goal reached!")
+			So(sent, ShouldContainSubstring, "That's 1 tomatos today")
+		})
+
+		Convey("Plain mode prints each period as it starts and ends", func() {
+			m := Tomato{
+				focusTime:        25 * time.Minute,
+				shortBreakTime:   5 * time.Minute,
+				longBreakTime:    15 * time.Minute,
+				longBreakTomatos: 4,
+			}
+			out := &bytes.Buffer{}
+			signals := make(chan os.Signal, 1)
+
+			// Each wait ends at once, moving the clock on, until the third
+			// period, which is interrupted.
+			clock := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+			waits := 0
+			clockNow = func() time.Time { return clock }
+			clockAfter = func(d time.Duration) <-chan time.Time {
+				ended := make(chan time.Time, 1)
+				if waits++; waits == 3 {
+					clock = clock.Add(d / 2)
+					signals <- os.Interrupt
+					return ended
+				}
+				clock = clock.Add(d)
+				ended <- clock
+				return ended
+			}
+			Reset(func() {
+				clockNow = time.Now
+				clockAfter = time.After
+			})
+
+			code := runPlain(m, out, signals)
+
+			So(code, ShouldEqual, exitInterrupted)
+			So(strings.Split(strings.TrimSpace(out.String()), "\n"), ShouldResemble, []string{
+				"2026-10-19T09:00:00Z focus started 25m",
+				"2026-10-19T09:25:00Z focus complete #1",
+				"2026-10-19T09:25:00Z short break started 5m",
+				"2026-10-19T09:30:00Z short break complete",
+				"2026-10-19T09:30:00Z focus started 25m",
+				"2026-10-19T09:42:30Z focus stopped",
+			})
+		})
+
+		Convey("Durations", func() {
+			Convey("name the flag they came from", func() {
+				opts := options{set: map[string]bool{"s": true}}
+				_, err := opts.parseDuration("s", "timer.short_break", "five")
+				So(err.Error(), ShouldStartWith, "reading -s:")
+			})
+
+			Convey("name the config key they came from", func() {
+				_, err := options{}.parseDuration("s", "timer.short_break", "0")
+				So(err.Error(), ShouldStartWith, "reading timer.short_break in the config file:")
+			})
+
+			Convey("accept bare minutes", func() {
+				d, err := options{}.parseDuration("s", "timer.short_break", "5")
+				So(err, ShouldBeNil)
+				So(d, ShouldEqual, 5*time.Minute)
+			})
 		})
 	})
 }
@@ -0,0 +1,45 @@
+package notifications
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// Backend names the mechanism used to deliver notifications.
+type Backend string
+
+const (
+	Kitty      Backend = "kitty"
+	NotifySend Backend = "notify-send"
+	NoBackend  Backend = "none"
+)
+
+func (b Backend) Valid() bool {
+	switch b {
+	case Kitty, NotifySend, NoBackend:
+		return true
+	}
+	return false
+}
+
+// Notifier sends notifications through a Backend. The zero value sends kitty
+// escape sequences to stdout.
+type Notifier struct {
+	Backend Backend
+	Output  SendEscapeSequence
+}
+
+func (n Notifier) Notify(title string, body string) {
+	switch n.Backend {
+	case NoBackend:
+		return
+	case NotifySend:
+		exec.Command("notify-send", title, body).Run()
+	default:
+		output := n.Output
+		if output == nil {
+			output = func(s string) { fmt.Print(s) }
+		}
+		NewNotification(title, body, Focus, output).Send()
+	}
+}
@@ -19,3 +19,64 @@ func TestNotifications(t *testing.T) {
 		})
 	})
 }
+
+func TestTemplates(t *testing.T) {
+	Convey("Template", t, func() {
+		vars := Vars{
+			TomatoCount:   3,
+			CyclePosition: 3,
+			CycleLength:   4,
+			Task:          "Refactor parser",
+			NextPhase:     "short break",
+			NextDuration:  "5m0s",
+		}
+
+		Convey("Renders variables into title and body", func() {
+			tmpl := Template{
+				Title: "Tomato {{.TomatoCount}} ({{.CyclePosition}}/{{.CycleLength}})",
+				Body:  "{{.Task}} done, {{.NextPhase}} for {{.NextDuration}}",
+			}
+
+			title, body, err := tmpl.Render(vars)
+			So(err, ShouldBeNil)
+			So(title, ShouldEqual, "Tomato 3 (3/4)")
+			So(body, ShouldEqual, "Refactor parser done, short break for 5m0s")
+		})
+
+		Convey("Picks the body from Messages when present", func() {
+			tmpl := Template{
+				Title:    "Title",
+				Body:     "Unused",
+				Messages: []string{"Next up: {{.NextPhase}}"},
+			}
+
+			_, body, err := tmpl.Render(vars)
+			So(err, ShouldBeNil)
+			So(body, ShouldEqual, "Next up: short break")
+		})
+
+		Convey("Reports template errors", func() {
+			tmpl := Template{Title: "{{.Nope"}
+
+			_, _, err := tmpl.Render(vars)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestNotifier(t *testing.T) {
+	Convey("Notifier", t, func() {
+		sequences := []string{}
+		output := func(s string) { sequences = append(sequences, s) }
+
+		Convey("Kitty backend sends escape sequences", func() {
+			Notifier{Backend: Kitty, Output: output}.Notify("Title", "Body")
+			So(len(sequences), ShouldEqual, 2)
+		})
+
+		Convey("None backend sends nothing", func() {
+			Notifier{Backend: NoBackend, Output: output}.Notify("Title", "Body")
+			So(sequences, ShouldBeEmpty)
+		})
+	})
+}
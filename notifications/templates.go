@@ -0,0 +1,105 @@
+package notifications
+
+import (
+	"bufio"
+	"bytes"
+	"math/rand"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Vars are the values available to notification templates, eg {{.TomatoCount}}.
+// Today and DailyGoal are set for the goal notification, and Project, Spent and
+// Budget for the budget one.
+type Vars struct {
+	Phase         string
+	Remaining     string
+	TomatoCount   int
+	CyclePosition int
+	CycleLength   int
+	Task          string
+	NextPhase     string
+	NextDuration  string
+	Today         int
+	DailyGoal     int
+	Project       string
+	Spent         string
+	Budget        string
+}
+
+// Template renders a notification title and body. If Messages is non-empty a
+// random entry from it is used in place of Body.
+type Template struct {
+	Title    string
+	Body     string
+	Messages []string
+}
+
+var random = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+func NewTemplate(title string, body string, messagesFile string) (Template, error) {
+	t := Template{Title: title, Body: body}
+	if messagesFile == "" {
+		return t, nil
+	}
+
+	messages, err := LoadMessages(messagesFile)
+	if err != nil {
+		return t, err
+	}
+	t.Messages = messages
+	return t, nil
+}
+
+// LoadMessages reads a file of rotating messages, one per line. Blank lines and
+// lines starting with # are ignored.
+func LoadMessages(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	messages := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		messages = append(messages, line)
+	}
+	return messages, scanner.Err()
+}
+
+func (t Template) Render(vars Vars) (title string, body string, err error) {
+	body = t.Body
+	if len(t.Messages) > 0 {
+		body = t.Messages[random.Intn(len(t.Messages))]
+	}
+
+	title, err = execute(t.Title, vars)
+	if err != nil {
+		return "", "", err
+	}
+	body, err = execute(body, vars)
+	if err != nil {
+		return "", "", err
+	}
+	return title, body, nil
+}
+
+func execute(text string, vars Vars) (string, error) {
+	tmpl, err := template.New("notification").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, vars); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/guysherman/tomato/duration"
+	"github.com/guysherman/tomato/history"
+	"github.com/guysherman/tomato/notifications"
+	"github.com/guysherman/tomato/timerview"
+)
+
+// Exit codes. Those for signals follow the shell convention of 128 plus the
+// signal number.
+const (
+	exitError       = 1
+	exitStopped     = 2
+	exitInterrupted = 130
+	exitTerminated  = 143
+)
+
+// isTerminal reports whether f is connected to a terminal, rather than a
+// pipe or a file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func notifySignals() <-chan os.Signal {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	return signals
+}
+
+// The clock is replaced in tests, so that periods end without waiting.
+var (
+	clockNow   = time.Now
+	clockAfter = time.After
+)
+
+// runPlain runs the focus/break cycle without a user interface, printing a
+// timestamped line to out as each period starts and ends. It runs until a
+// signal arrives, and returns the exit code to use.
+func runPlain(m Tomato, out io.Writer, signals <-chan os.Signal) int {
+	m = plainNotifier(m)
+	for {
+		var completed bool
+		var sig os.Signal
+		m, completed, sig = runPlainPeriod(m, out, signals)
+		if !completed {
+			return exitCode(sig)
+		}
+		m = m.advance()
+	}
+}
+
+// plainNotifier turns off kitty notifications, which are escape sequences on
+// stdout that would end up in the log.
+func plainNotifier(m Tomato) Tomato {
+	if m.notifier.Backend == notifications.Kitty || m.notifier.Backend == "" {
+		m.notifier.Backend = notifications.NoBackend
+	}
+	return m
+}
+
+// runPlainPeriod runs the current period to completion, sending its warnings
+// and notification on the way, unless a signal arrives first. It returns the
+// model with the period recorded.
+func runPlainPeriod(m Tomato, out io.Writer, signals <-chan os.Signal) (Tomato, bool, os.Signal) {
+	length := m.durationForMode()
+	opts := m.modeOptions()
+	if m.mode == focus {
+		timerview.RunScript(m.quietModeScript)
+	}
+	printEvent(out, "%s started %s", m.mode, duration.Format(length))
+
+	warnings := pendingWarnings(opts.Warnings, length)
+	start := clockNow()
+	for {
+		next := length
+		if len(warnings) > 0 {
+			next = length - warnings[0].Before
+		}
+
+		select {
+		case sig := <-signals:
+			if m.mode == focus {
+				timerview.RunScript(m.noiseModeScript)
+			}
+			printEvent(out, "%s stopped", m.mode)
+			m, _, notify := m.record(plainPeriod(start, length), history.Stopped)
+			if notify != nil {
+				notify()
+			}
+			return m, false, sig
+		case <-clockAfter(next - clockNow().Sub(start)):
+		}
+
+		if len(warnings) > 0 {
+			w := warnings[0]
+			warnings = warnings[1:]
+			m.notifier.Notify(w.Title, w.Body)
+			if w.Hook != "" {
+				timerview.RunScript(w.Hook)
+			}
+			printEvent(out, "%s ends in %s", m.mode, duration.Format(w.Before))
+			continue
+		}
+		break
+	}
+
+	if m.mode == focus {
+		timerview.RunScript(m.noiseModeScript)
+		printEvent(out, "%s complete #%d", m.mode, m.tomatoCount+1)
+	} else {
+		printEvent(out, "%s complete", m.mode)
+	}
+	m.notifier.Notify(opts.NotificationTitle, opts.NotificationBody)
+	m, _, notify := m.record(plainPeriod(start, length), history.Completed)
+	if notify != nil {
+		notify()
+	}
+	return m, true, nil
+}
+
+func plainPeriod(start time.Time, length time.Duration) timerview.Period {
+	return timerview.Period{
+		Started: start,
+		Planned: length,
+		Elapsed: clockNow().Sub(start).Round(time.Second),
+	}
+}
+
+// pendingWarnings are the warnings that fall inside a period of the given
+// length, in the order they fire.
+func pendingWarnings(warnings []timerview.Warning, length time.Duration) []timerview.Warning {
+	pending := []timerview.Warning{}
+	for _, w := range warnings {
+		if w.Before > 0 && w.Before < length {
+			pending = append(pending, w)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].Before > pending[j].Before
+	})
+	return pending
+}
+
+func (m Tomato) durationForMode() time.Duration {
+	switch m.mode {
+	case shortBreak:
+		return m.shortBreakTime
+	case longBreak:
+		return m.longBreakTime
+	default:
+		return m.focusTime
+	}
+}
+
+func exitCode(sig os.Signal) int {
+	if sig == syscall.SIGTERM {
+		return exitTerminated
+	}
+	return exitInterrupted
+}
+
+func printEvent(out io.Writer, format string, a ...interface{}) {
+	fmt.Fprintf(out, "%s %s\n", clockNow().Format(time.RFC3339), fmt.Sprintf(format, a...))
+}
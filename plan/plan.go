@@ -0,0 +1,134 @@
+package plan
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Item is a task planned for the day, with the number of tomatos it's
+// expected to take and the number spent on it so far.
+type Item struct {
+	Task     string `json:"task"`
+	Estimate int    `json:"estimate"`
+	Spent    int    `json:"spent,omitempty"`
+	Done     bool   `json:"done,omitempty"`
+}
+
+// Left is the number of tomatos the estimate has left, which is zero once the
+// item is done or has overrun.
+func (i Item) Left() int {
+	if i.Done || i.Spent >= i.Estimate {
+		return 0
+	}
+	return i.Estimate - i.Spent
+}
+
+// Plan is the list of tasks to work through on a day, in order. Date is
+// formatted as yyyy-mm-dd.
+type Plan struct {
+	Date  string `json:"date"`
+	Items []Item `json:"items"`
+}
+
+// Current is the index of the item being worked on, the first that still has
+// tomatos left, or -1 once everything is done.
+func (p Plan) Current() int {
+	for i, item := range p.Items {
+		if item.Left() > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// Estimated is the number of tomatos the whole plan is expected to take.
+func (p Plan) Estimated() int {
+	total := 0
+	for _, item := range p.Items {
+		total += item.Estimate
+	}
+	return total
+}
+
+// Left is the number of tomatos still to do.
+func (p Plan) Left() int {
+	total := 0
+	for _, item := range p.Items {
+		total += item.Left()
+	}
+	return total
+}
+
+// Credit counts a tomato towards the first item for task that has tomatos
+// left, or failing that the last item for task. It reports whether the task
+// was in the plan.
+func (p Plan) Credit(task string) (Plan, bool) {
+	match := -1
+	for i, item := range p.Items {
+		if item.Task != task {
+			continue
+		}
+		match = i
+		if item.Left() > 0 {
+			break
+		}
+	}
+	if match < 0 {
+		return p, false
+	}
+
+	items := append([]Item{}, p.Items...)
+	items[match].Spent++
+	p.Items = items
+	return p, true
+}
+
+// Store keeps the day's plan in a JSON file. A Store with no Path keeps
+// nothing.
+type Store struct {
+	Path string
+}
+
+// Load reads the plan for date. A missing file, or a plan left over from
+// another day, is an empty plan.
+func (s Store) Load(date string) (Plan, error) {
+	empty := Plan{Date: date, Items: []Item{}}
+	if s.Path == "" {
+		return empty, nil
+	}
+
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return empty, nil
+	} else if err != nil {
+		return empty, err
+	}
+
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return empty, err
+	}
+	if p.Date != date {
+		return empty, nil
+	}
+	return p, nil
+}
+
+// Save replaces the plan in the file.
+func (s Store) Save(p Plan) error {
+	if s.Path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path, data, 0644)
+}
@@ -0,0 +1,80 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPlan(t *testing.T) {
+	Convey("Plan", t, func() {
+		p := Plan{Date: "2026-10-19", Items: []Item{
+			{Task: "Refactor parser", Estimate: 2},
+			{Task: "Write tests", Estimate: 3},
+			{Task: "Review PRs", Estimate: 1, Done: true},
+		}}
+
+		Convey("works through the items in order", func() {
+			So(p.Current(), ShouldEqual, 0)
+			So(p.Estimated(), ShouldEqual, 6)
+			So(p.Left(), ShouldEqual, 5)
+
+			p, _ = p.Credit("Refactor parser")
+			p, _ = p.Credit("Refactor parser")
+			So(p.Current(), ShouldEqual, 1)
+			So(p.Left(), ShouldEqual, 3)
+		})
+
+		Convey("overruns are counted against the task", func() {
+			p, _ = p.Credit("Refactor parser")
+			p, _ = p.Credit("Refactor parser")
+			p, ok := p.Credit("Refactor parser")
+			So(ok, ShouldBeTrue)
+			So(p.Items[0].Spent, ShouldEqual, 3)
+			So(p.Items[0].Left(), ShouldEqual, 0)
+		})
+
+		Convey("tasks outside the plan aren't credited", func() {
+			credited, ok := p.Credit("Lunch")
+			So(ok, ShouldBeFalse)
+			So(credited, ShouldResemble, p)
+		})
+
+		Convey("everything done has no current item", func() {
+			So(Plan{Items: []Item{{Task: "Review PRs", Estimate: 1, Spent: 1}}}.Current(), ShouldEqual, -1)
+		})
+	})
+
+	Convey("Store", t, func() {
+		store := Store{Path: filepath.Join(t.TempDir(), "tomato", "plan.json")}
+
+		Convey("Missing file is an empty plan", func() {
+			p, err := store.Load("2026-10-19")
+			So(err, ShouldBeNil)
+			So(p.Date, ShouldEqual, "2026-10-19")
+			So(p.Items, ShouldBeEmpty)
+		})
+
+		Convey("Saved plans are loaded back on the same day", func() {
+			p := Plan{Date: "2026-10-19", Items: []Item{{Task: "Write tests", Estimate: 3, Spent: 1}}}
+			So(store.Save(p), ShouldBeNil)
+
+			loaded, err := store.Load("2026-10-19")
+			So(err, ShouldBeNil)
+			So(loaded, ShouldResemble, p)
+
+			loaded, err = store.Load("2026-10-20")
+			So(err, ShouldBeNil)
+			So(loaded.Items, ShouldBeEmpty)
+		})
+
+		Convey("Malformed file is an error", func() {
+			os.MkdirAll(filepath.Dir(store.Path), 0755)
+			os.WriteFile(store.Path, []byte("{"), 0644)
+			_, err := store.Load("2026-10-19")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
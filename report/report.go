@@ -0,0 +1,307 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guysherman/tomato/duration"
+	"github.com/guysherman/tomato/history"
+)
+
+// Grouping is what the rows of a report are.
+type Grouping string
+
+const (
+	ByProject Grouping = "project"
+	ByTask    Grouping = "task"
+	ByTag     Grouping = "tag"
+)
+
+// Valid reports whether g is one of the groupings.
+func (g Grouping) Valid() bool {
+	return g == ByProject || g == ByTask || g == ByTag
+}
+
+func (g Grouping) heading() string {
+	switch g {
+	case ByTask:
+		return "Task"
+	case ByTag:
+		return "Tag"
+	default:
+		return "Project"
+	}
+}
+
+// Rounding is the direction times are rounded in.
+type Rounding string
+
+const (
+	Up      Rounding = "up"
+	Nearest Rounding = "nearest"
+	Down    Rounding = "down"
+)
+
+// Valid reports whether r is one of the roundings.
+func (r Rounding) Valid() bool {
+	return r == Up || r == Nearest || r == Down
+}
+
+// Rules are how the time worked each day is turned into time billed. Minimum
+// is the least billed for a day with any time on it, and the result is then
+// rounded to a multiple of Round, in the direction of Rounding. A zero Round
+// leaves the time as it is.
+type Rules struct {
+	Round    time.Duration
+	Rounding Rounding
+	Minimum  time.Duration
+}
+
+// Apply turns the time worked into the time billed.
+func (r Rules) Apply(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	if d < r.Minimum {
+		d = r.Minimum
+	}
+	if r.Round <= 0 {
+		return d
+	}
+
+	switch r.Rounding {
+	case Down:
+		return d / r.Round * r.Round
+	case Nearest:
+		return (d + r.Round/2) / r.Round * r.Round
+	default:
+		return (d + r.Round - 1) / r.Round * r.Round
+	}
+}
+
+// String describes the rules, eg "rounded up to 15m, minimum 30m".
+func (r Rules) String() string {
+	parts := []string{}
+	if r.Round > 0 {
+		parts = append(parts, fmt.Sprintf("rounded %s to %s", r.rounding(), duration.Format(r.Round)))
+	}
+	if r.Minimum > 0 {
+		parts = append(parts, fmt.Sprintf("minimum %s", duration.Format(r.Minimum)))
+	}
+	if len(parts) == 0 {
+		return "not rounded"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (r Rules) rounding() Rounding {
+	if r.Rounding == "" {
+		return Up
+	}
+	return r.Rounding
+}
+
+// Row is the time billed to one project, task or tag on each day, and in
+// total.
+type Row struct {
+	Name  string
+	Days  []time.Duration
+	Total time.Duration
+}
+
+// Report is a timesheet: the time billed each day, starting from Start, with
+// a row per project, task or tag.
+type Report struct {
+	By     Grouping
+	Start  time.Time
+	Days   int
+	Rules  Rules
+	Rows   []Row
+	Totals []time.Duration
+	Total  time.Duration
+}
+
+// Build works out the timesheet for the days from start, from the focus
+// periods in the history. Stopped periods are left out, and the rest count for
+// the time they actually ran, overtime included. A period counts on the day it
+// started.
+func Build(records []history.Record, by Grouping, rules Rules, start time.Time, days int) Report {
+	end := start.AddDate(0, 0, days)
+	worked := map[string][]time.Duration{}
+	for _, r := range records {
+		if !r.IsFocus() || r.Outcome == history.Stopped {
+			continue
+		}
+		started := r.Start.In(start.Location())
+		if started.Before(start) || !started.Before(end) {
+			continue
+		}
+		day := dayIndex(start, started)
+		for _, name := range names(r, by) {
+			if worked[name] == nil {
+				worked[name] = make([]time.Duration, days)
+			}
+			worked[name][day] += r.Actual
+		}
+	}
+
+	report := Report{By: by, Start: start, Days: days, Rules: rules, Totals: make([]time.Duration, days)}
+	for name, times := range worked {
+		row := Row{Name: name, Days: make([]time.Duration, days)}
+		for day, d := range times {
+			billed := rules.Apply(d)
+			row.Days[day] = billed
+			row.Total += billed
+			report.Totals[day] += billed
+			report.Total += billed
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool { return report.Rows[i].Name < report.Rows[j].Name })
+	return report
+}
+
+// dayIndex counts the calendar days from start to t, which is safe across
+// daylight saving changes.
+func dayIndex(start time.Time, t time.Time) int {
+	day := 0
+	for !t.Before(start.AddDate(0, 0, day+1)) {
+		day++
+	}
+	return day
+}
+
+// names are the rows a record is billed to. A record with several tags is
+// billed to each of them.
+func names(r history.Record, by Grouping) []string {
+	switch by {
+	case ByTask:
+		if r.Task == "" {
+			return []string{"(no task)"}
+		}
+		return []string{r.Task}
+	case ByTag:
+		if len(r.Tags) == 0 {
+			return []string{"(no tag)"}
+		}
+		return r.Tags
+	default:
+		if r.Project == "" {
+			return []string{"(no project)"}
+		}
+		return []string{r.Project}
+	}
+}
+
+// Hours formats a duration as decimal hours, eg 1.25, for billing.
+func Hours(d time.Duration) string {
+	return fmt.Sprintf("%.2f", d.Hours())
+}
+
+// WriteTable writes the report as a table, with a column per day, for reading
+// in a terminal. Days with no time are shown as -.
+func (r Report) WriteTable(w io.Writer) error {
+	rows := [][]string{{r.By.heading()}}
+	for day := 0; day < r.Days; day++ {
+		rows[0] = append(rows[0], r.Start.AddDate(0, 0, day).Format("Mon 02"))
+	}
+	rows[0] = append(rows[0], "Total")
+	for _, row := range r.Rows {
+		rows = append(rows, cells(row.Name, row.Days, row.Total, "-"))
+	}
+	rows = append(rows, cells("Total", r.Totals, r.Total, "-"))
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := len([]rune(cell)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s, %s\n\n", r.title(), r.Rules)
+	for _, row := range rows {
+		fmt.Fprintf(&b, "%-*s", widths[0], row[0])
+		for i, cell := range row[1:] {
+			fmt.Fprintf(&b, "  %*s", widths[i+1], cell)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteCSV writes the report with a column per day, named by its date, and
+// the hours as decimals.
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{string(r.By)}
+	for day := 0; day < r.Days; day++ {
+		header = append(header, r.Start.AddDate(0, 0, day).Format("2006-01-02"))
+	}
+	header = append(header, "total")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range r.Rows {
+		if err := cw.Write(cells(row.Name, row.Days, row.Total, Hours(0))); err != nil {
+			return err
+		}
+	}
+	if err := cw.Write(cells("total", r.Totals, r.Total, Hours(0))); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func cells(name string, days []time.Duration, total time.Duration, none string) []string {
+	cells := []string{name}
+	for _, d := range days {
+		if d == 0 {
+			cells = append(cells, none)
+		} else {
+			cells = append(cells, Hours(d))
+		}
+	}
+	return append(cells, Hours(total))
+}
+
+func (r Report) title() string {
+	last := r.Start.AddDate(0, 0, r.Days-1)
+	if year, week := r.Start.ISOWeek(); r.Days == 7 && r.Start.Weekday() == time.Monday {
+		return fmt.Sprintf("Week %d-W%02d, %s to %s", year, week, r.Start.Format("Mon 2 Jan"), last.Format("Mon 2 Jan"))
+	}
+	return fmt.Sprintf("%s to %s", r.Start.Format("Mon 2 Jan 2006"), last.Format("Mon 2 Jan 2006"))
+}
+
+// isoWeek matches an ISO 8601 week, eg 2026-W42.
+var isoWeek = regexp.MustCompile(`^(\d{4})-W(\d{2})$`)
+
+// ParseWeek reads an ISO 8601 week, eg 2026-W42, and returns the Monday it
+// starts on, at midnight in loc.
+func ParseWeek(s string, loc *time.Location) (time.Time, error) {
+	parts := isoWeek.FindStringSubmatch(s)
+	if parts == nil {
+		return time.Time{}, fmt.Errorf("expected a week as yyyy-Www, eg 2026-W42, not %q", s)
+	}
+	year, _ := strconv.Atoi(parts[1])
+	week, _ := strconv.Atoi(parts[2])
+
+	// The 4th of January is always in the first week of the year.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	monday := jan4.AddDate(0, 0, -(int(jan4.Weekday())+6)%7+7*(week-1))
+	if y, w := monday.ISOWeek(); week < 1 || y != year || w != week {
+		return time.Time{}, fmt.Errorf("%d has no week %d", year, week)
+	}
+	return monday, nil
+}
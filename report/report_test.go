@@ -0,0 +1,106 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guysherman/tomato/history"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReport(t *testing.T) {
+	Convey("Report", t, func() {
+		monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+		focus := func(day int, hour int, project string, actual time.Duration, outcome history.Outcome) history.Record {
+			return history.Record{
+				Start:   monday.AddDate(0, 0, day).Add(time.Duration(hour) * time.Hour),
+				Phase:   "focus",
+				Task:    "Fix login",
+				Project: project,
+				Tags:    []string{"bug", "acme"},
+				Outcome: outcome,
+				Actual:  actual,
+			}
+		}
+		records := []history.Record{
+			focus(0, 9, "acme", 25*time.Minute, history.Completed),
+			focus(0, 10, "acme", 31*time.Minute, history.Completed),
+			focus(0, 11, "acme", 20*time.Minute, history.Stopped),
+			focus(1, 9, "globex", 10*time.Minute, history.Skipped),
+			focus(2, 9, "", 25*time.Minute, history.Completed),
+			focus(7, 9, "acme", 25*time.Minute, history.Completed),
+			{Start: monday.Add(12 * time.Hour), Phase: "short break", Project: "acme", Outcome: history.Completed, Actual: 5 * time.Minute},
+		}
+
+		Convey("rules set a minimum, and round", func() {
+			rules := Rules{Round: 15 * time.Minute, Rounding: Up, Minimum: 30 * time.Minute}
+			So(rules.Apply(0), ShouldEqual, 0)
+			So(rules.Apply(10*time.Minute), ShouldEqual, 30*time.Minute)
+			So(rules.Apply(56*time.Minute), ShouldEqual, time.Hour)
+			So(Rules{Round: 15 * time.Minute, Rounding: Nearest}.Apply(52*time.Minute), ShouldEqual, 45*time.Minute)
+			So(Rules{Round: 15 * time.Minute, Rounding: Down}.Apply(59*time.Minute), ShouldEqual, 45*time.Minute)
+			So(rules.String(), ShouldEqual, "rounded up to 15m, minimum 30m")
+		})
+
+		Convey("totals each project per day, leaving out stopped periods and other weeks", func() {
+			r := Build(records, ByProject, Rules{Round: 15 * time.Minute, Rounding: Up}, monday, 7)
+			So(r.Rows, ShouldHaveLength, 3)
+			So(r.Rows[0].Name, ShouldEqual, "(no project)")
+			So(r.Rows[1].Name, ShouldEqual, "acme")
+			So(r.Rows[1].Days[0], ShouldEqual, time.Hour)
+			So(r.Rows[1].Total, ShouldEqual, time.Hour)
+			So(r.Rows[2].Days[1], ShouldEqual, 15*time.Minute)
+			So(r.Total, ShouldEqual, time.Hour+15*time.Minute+30*time.Minute)
+		})
+
+		Convey("bills records to each of their tags", func() {
+			r := Build(records, ByTag, Rules{}, monday, 7)
+			So(r.Rows, ShouldHaveLength, 2)
+			So(r.Rows[0].Name, ShouldEqual, "acme")
+			So(r.Rows[0].Total, ShouldEqual, r.Rows[1].Total)
+		})
+
+		Convey("writes a table", func() {
+			out := &bytes.Buffer{}
+			r := Build(records, ByProject, Rules{Round: 15 * time.Minute}, monday, 7)
+			So(r.WriteTable(out), ShouldBeNil)
+			lines := strings.Split(out.String(), "\n")
+			So(lines[0], ShouldEqual, "Week 2026-W42, Mon 12 Oct to Sun 18 Oct, rounded up to 15m")
+			So(lines[2], ShouldStartWith, "Project       Mon 12  Tue 13")
+			So(lines[4], ShouldEqual, "acme            1.00       -       -       -       -       -       -   1.00")
+			So(lines[6], ShouldStartWith, "Total           1.00    0.25    0.50")
+		})
+
+		Convey("writes CSV", func() {
+			out := &bytes.Buffer{}
+			r := Build(records, ByProject, Rules{}, monday, 7)
+			So(r.WriteCSV(out), ShouldBeNil)
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			So(lines, ShouldHaveLength, 5)
+			So(lines[0], ShouldEqual, "project,2026-10-12,2026-10-13,2026-10-14,2026-10-15,2026-10-16,2026-10-17,2026-10-18,total")
+			So(lines[2], ShouldEqual, "acme,0.93,0.00,0.00,0.00,0.00,0.00,0.00,0.93")
+			So(lines[4], ShouldEqual, "total,0.93,0.17,0.42,0.00,0.00,0.00,0.00,1.52")
+		})
+
+		Convey("ParseWeek finds the Monday of an ISO week", func() {
+			start, err := ParseWeek("2026-W42", time.UTC)
+			So(err, ShouldBeNil)
+			So(start, ShouldEqual, monday)
+
+			start, err = ParseWeek("2026-W01", time.UTC)
+			So(err, ShouldBeNil)
+			So(start, ShouldEqual, time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC))
+
+			_, err = ParseWeek("2026-W54", time.UTC)
+			So(err, ShouldNotBeNil)
+			_, err = ParseWeek("last week", time.UTC)
+			So(err, ShouldNotBeNil)
+			for _, s := range []string{"2026-W4x", "2026-W+1", " 2026-W42", "2026-w42"} {
+				_, err = ParseWeek(s, time.UTC)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+}
@@ -0,0 +1,111 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/guysherman/tomato/history"
+)
+
+// bars are the eighths of a block used to draw bar charts and sparklines.
+var bars = []rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// shades are the levels of the calendar heatmap, from nothing to the busiest
+// days.
+var shades = []rune{'·', '░', '▒', '▓', '█'}
+
+func maxOf(values []int) int {
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+// barChart draws values as vertical bars height lines tall, each bar width
+// columns wide with a gap between them. The scale is marked on the left.
+func barChart(values []int, height int, width int) []string {
+	max := maxOf(values)
+	scale := fmt.Sprint(max)
+	lines := []string{}
+	for row := height - 1; row >= 0; row-- {
+		label := strings.Repeat(" ", len(scale))
+		if row == height-1 {
+			label = scale
+		} else if row == 0 {
+			label = fmt.Sprintf("%*d", len(scale), 0)
+		}
+
+		var b strings.Builder
+		b.WriteString(label + " │")
+		for _, v := range values {
+			eighths := 0
+			if max > 0 {
+				eighths = v*height*8/max - row*8
+			}
+			if eighths < 0 {
+				eighths = 0
+			} else if eighths > 8 {
+				eighths = 8
+			}
+			b.WriteString(strings.Repeat(string(bars[eighths]), width) + " ")
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+	return lines
+}
+
+// sparkline draws values as a single line of bars.
+func sparkline(values []int) string {
+	max := maxOf(values)
+	var b strings.Builder
+	for _, v := range values {
+		eighths := 0
+		if max > 0 {
+			eighths = (v*8 + max - 1) / max
+		}
+		b.WriteRune(bars[eighths])
+	}
+	return b.String()
+}
+
+// heatmap draws a calendar of the weeks up to and including the one holding
+// last, with a column per week and a row per day from Monday to Sunday, shaded
+// by how many tomatos were completed that day.
+func heatmap(counts map[string]int, last time.Time, weeks int) []string {
+	max := 0
+	for _, c := range counts {
+		if c > max {
+			max = c
+		}
+	}
+
+	monday := history.StartOfWeek(last).AddDate(0, 0, -7*(weeks-1))
+	days := []string{"Mon", "", "Wed", "", "Fri", "", "Sun"}
+	lines := []string{}
+	for row := 0; row < 7; row++ {
+		var b strings.Builder
+		b.WriteString(fmt.Sprintf("%-3s ", days[row]))
+		for week := 0; week < weeks; week++ {
+			day := monday.AddDate(0, 0, week*7+row)
+			if day.After(last) {
+				b.WriteRune(' ')
+				continue
+			}
+			b.WriteRune(shade(counts[history.DayKey(day)], max))
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+	return lines
+}
+
+func shade(count int, max int) rune {
+	if count == 0 || max == 0 {
+		return shades[0]
+	}
+	level := (count*(len(shades)-1) + max - 1) / max
+	return shades[level]
+}
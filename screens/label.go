@@ -0,0 +1,130 @@
+package screens
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guysherman/tomato/timerview"
+)
+
+// LabelledMsg is sent when the project and tags for the coming focus periods
+// have been set, or the prompt was cancelled.
+type LabelledMsg struct {
+	Project   string
+	Tags      []string
+	Cancelled bool
+}
+
+// LabelKeyMap holds the key bindings for the label prompt.
+type LabelKeyMap struct {
+	Save   key.Binding
+	Cancel key.Binding
+}
+
+func DefaultLabelKeyMap() LabelKeyMap {
+	return NewLabelKeyMap(timerview.DefaultKeyMap())
+}
+
+// NewLabelKeyMap takes the label prompt's bindings from the app's key map.
+func NewLabelKeyMap(k timerview.KeyMap) LabelKeyMap {
+	return LabelKeyMap{
+		Save:   k.Binding("label.save"),
+		Cancel: k.Binding("label.cancel"),
+	}
+}
+
+func (k LabelKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Save, k.Cancel}
+}
+
+func (k LabelKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// Label is a quick prompt for the project and tags that focus periods are
+// recorded with, written as the project followed by #tags, eg
+// "acme #review #urgent".
+type Label struct {
+	input textinput.Model
+	keys  LabelKeyMap
+	help  help.Model
+}
+
+func NewLabel(project string, tags []string) Label {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "project #tag #tag"
+	input.SetValue(FormatLabel(project, tags))
+	input.CursorEnd()
+	input.Focus()
+
+	return Label{
+		input: input,
+		keys:  DefaultLabelKeyMap(),
+		help:  help.NewModel(),
+	}
+}
+
+// SetKeys rebinds the prompt's keys.
+func (m Label) SetKeys(keys LabelKeyMap) Label {
+	m.keys = keys
+	return m
+}
+
+func (m Label) Update(msg tea.Msg) (Label, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, m.keys.Save):
+			project, tags := ParseLabel(m.input.Value())
+			return m, func() tea.Msg { return LabelledMsg{Project: project, Tags: tags} }
+		case key.Matches(keyMsg, m.keys.Cancel):
+			return m, func() tea.Msg { return LabelledMsg{Cancelled: true} }
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m Label) View() string {
+	title := lipgloss.NewStyle().Bold(true)
+	lines := []string{
+		title.Render("Which project, and what tags?"),
+		m.input.View(),
+		"",
+		m.help.View(m.keys),
+	}
+	return screenStyle.Render(strings.Join(lines, "\n"))
+}
+
+// ParseLabel splits a label into the project, which is every word that isn't
+// a #tag, and the tags, without their #.
+func ParseLabel(s string) (string, []string) {
+	project := []string{}
+	tags := []string{}
+	for _, word := range strings.Fields(s) {
+		if len(word) > 1 && strings.HasPrefix(word, "#") {
+			tags = append(tags, strings.TrimPrefix(word, "#"))
+		} else {
+			project = append(project, word)
+		}
+	}
+	return strings.Join(project, " "), tags
+}
+
+// FormatLabel writes a project and tags the way ParseLabel reads them.
+func FormatLabel(project string, tags []string) string {
+	words := []string{}
+	if project != "" {
+		words = append(words, project)
+	}
+	for _, tag := range tags {
+		words = append(words, "#"+tag)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,328 @@
+package screens
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guysherman/tomato/duration"
+	"github.com/guysherman/tomato/plan"
+	"github.com/guysherman/tomato/timerview"
+)
+
+// PlanChangedMsg is sent when the day's plan has been edited.
+type PlanChangedMsg struct {
+	Plan plan.Plan
+}
+
+// PlanKeyMap holds the key bindings for the plan screen.
+type PlanKeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	MoveUp   key.Binding
+	MoveDown key.Binding
+	Add      key.Binding
+	More     key.Binding
+	Less     key.Binding
+	Done     key.Binding
+	Delete   key.Binding
+}
+
+func DefaultPlanKeyMap() PlanKeyMap {
+	return NewPlanKeyMap(timerview.DefaultKeyMap())
+}
+
+// NewPlanKeyMap takes the plan screen's bindings from the app's key map.
+func NewPlanKeyMap(k timerview.KeyMap) PlanKeyMap {
+	return PlanKeyMap{
+		Up:       k.Binding("plan.up"),
+		Down:     k.Binding("plan.down"),
+		MoveUp:   k.Binding("plan.move_up"),
+		MoveDown: k.Binding("plan.move_down"),
+		Add:      k.Binding("plan.add"),
+		More:     k.Binding("plan.more"),
+		Less:     k.Binding("plan.less"),
+		Done:     k.Binding("plan.done"),
+		Delete:   k.Binding("plan.delete"),
+	}
+}
+
+func (k PlanKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.MoveUp, k.MoveDown, k.Add, k.More, k.Less, k.Done, k.Delete}
+}
+
+func (k PlanKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// Availability is how much focus time is left in the working day.
+type Availability struct {
+	Tomatos int
+	Until   time.Time
+	Focus   time.Duration
+}
+
+// planStep is where adding a task to the plan is up to.
+type planStep int
+
+const (
+	notAdding planStep = iota
+	namingTask
+	estimatingTask
+)
+
+// Plan is the daily planning screen. It lists the day's tasks with the
+// number of tomatos each is expected to take, which are worked through in
+// order, against the time there is left to do them in.
+type Plan struct {
+	plan      plan.Plan
+	store     plan.Store
+	cursor    int
+	step      planStep
+	name      string
+	input     textinput.Model
+	available Availability
+	keys      PlanKeyMap
+	help      help.Model
+	width     int
+	height    int
+	err       error
+}
+
+func NewPlan(store plan.Store, p plan.Plan) Plan {
+	return Plan{
+		plan:  p,
+		store: store,
+		input: textinput.New(),
+		keys:  DefaultPlanKeyMap(),
+		help:  help.NewModel(),
+	}
+}
+
+// SetKeys rebinds the screen's keys.
+func (m Plan) SetKeys(keys PlanKeyMap) Plan {
+	m.keys = keys
+	return m
+}
+
+// Plan returns the day's plan.
+func (m Plan) Plan() plan.Plan {
+	return m.plan
+}
+
+// Editing reports whether a task is being added, in which case every key
+// should go to the screen.
+func (m Plan) Editing() bool {
+	return m.step != notAdding
+}
+
+// SetAvailable updates the time left in the day that the plan is measured
+// against.
+func (m Plan) SetAvailable(a Availability) Plan {
+	m.available = a
+	return m
+}
+
+// Credit counts a completed tomato towards the task, and saves the plan if
+// the task is in it.
+func (m Plan) Credit(task string) Plan {
+	credited, ok := m.plan.Credit(task)
+	if !ok {
+		return m
+	}
+	m.plan = credited
+	return m.save()
+}
+
+func (m Plan) Update(msg tea.Msg) (Plan, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		return m, nil
+	case tea.KeyMsg:
+		if m.step != notAdding {
+			return m.handleInput(msg)
+		}
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m Plan) handleKey(msg tea.KeyMsg) (Plan, tea.Cmd) {
+	if key.Matches(msg, m.keys.Add) {
+		m.step = namingTask
+		m.input.Prompt = "Task: "
+		m.input.Placeholder = "What needs doing today?"
+		m.input.SetValue("")
+		return m, m.input.Focus()
+	}
+
+	items := m.plan.Items
+	if len(items) == 0 {
+		return m, nil
+	}
+	items = append([]plan.Item{}, items...)
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.Down):
+		if m.cursor < len(items)-1 {
+			m.cursor++
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.MoveUp):
+		if m.cursor == 0 {
+			return m, nil
+		}
+		items[m.cursor-1], items[m.cursor] = items[m.cursor], items[m.cursor-1]
+		m.cursor--
+	case key.Matches(msg, m.keys.MoveDown):
+		if m.cursor == len(items)-1 {
+			return m, nil
+		}
+		items[m.cursor+1], items[m.cursor] = items[m.cursor], items[m.cursor+1]
+		m.cursor++
+	case key.Matches(msg, m.keys.More):
+		items[m.cursor].Estimate++
+	case key.Matches(msg, m.keys.Less):
+		if items[m.cursor].Estimate <= 1 {
+			return m, nil
+		}
+		items[m.cursor].Estimate--
+	case key.Matches(msg, m.keys.Done):
+		items[m.cursor].Done = !items[m.cursor].Done
+	case key.Matches(msg, m.keys.Delete):
+		items = append(items[:m.cursor], items[m.cursor+1:]...)
+		if m.cursor >= len(items) && m.cursor > 0 {
+			m.cursor--
+		}
+	default:
+		return m, nil
+	}
+	return m.change(items)
+}
+
+func (m Plan) handleInput(msg tea.KeyMsg) (Plan, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		value := strings.TrimSpace(m.input.Value())
+		if m.step == namingTask {
+			if value == "" {
+				return m.stopAdding(), nil
+			}
+			m.name = value
+			m.step = estimatingTask
+			m.input.Prompt = "Tomatos: "
+			m.input.Placeholder = "1"
+			m.input.SetValue("")
+			return m, nil
+		}
+
+		estimate := 1
+		if value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				m.err = fmt.Errorf("the estimate must be a whole number of tomatos, at least 1")
+				return m, nil
+			}
+			estimate = n
+		}
+		m = m.stopAdding()
+		items := append(append([]plan.Item{}, m.plan.Items...), plan.Item{Task: m.name, Estimate: estimate})
+		m.cursor = len(items) - 1
+		return m.change(items)
+	case tea.KeyEsc:
+		return m.stopAdding(), nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m Plan) stopAdding() Plan {
+	m.step = notAdding
+	m.err = nil
+	m.input.Blur()
+	return m
+}
+
+// change replaces the items in the plan, saves it and lets the timer know.
+func (m Plan) change(items []plan.Item) (Plan, tea.Cmd) {
+	m.plan.Items = items
+	m = m.save()
+	p := m.plan
+	return m, func() tea.Msg { return PlanChangedMsg{Plan: p} }
+}
+
+func (m Plan) save() Plan {
+	m.err = m.store.Save(m.plan)
+	return m
+}
+
+func (m Plan) View() string {
+	title := lipgloss.NewStyle().Bold(true)
+	lines := []string{title.Render("Today's plan"), ""}
+	if len(m.plan.Items) == 0 {
+		lines = append(lines, "Nothing planned yet, press a to add a task.")
+	}
+
+	current := m.plan.Current()
+	for i, item := range m.plan.Items {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		check := "[ ]"
+		if item.Done {
+			check = "[x]"
+		}
+		line := fmt.Sprintf("%s%s %-30s %d/%d", cursor, check, item.Task, item.Spent, item.Estimate)
+		if i == current {
+			line = title.Render(line + "  🍅")
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "", m.summary())
+	lines = append(lines, "")
+	if m.step != notAdding {
+		lines = append(lines, m.input.View())
+	} else {
+		lines = append(lines, m.help.View(m.keys))
+	}
+	if m.err != nil {
+		lines = append(lines, fmt.Sprintf("Error: %v", m.err))
+	}
+	return screenStyle.Render(strings.Join(lines, "\n"))
+}
+
+// summary compares the tomatos still to do with the time left to do them in.
+func (m Plan) summary() string {
+	left := m.plan.Left()
+	summary := fmt.Sprintf("%d of %d 🍅 left", left, m.plan.Estimated())
+	if m.available.Focus > 0 {
+		summary = fmt.Sprintf("%s (%s of focus)", summary, duration.FormatHours(time.Duration(left)*m.available.Focus))
+	}
+	if m.available.Until.IsZero() {
+		return summary
+	}
+
+	summary = fmt.Sprintf("%s, room for %d before %s", summary, m.available.Tomatos, m.available.Until.Format("15:04"))
+	if over := left - m.available.Tomatos; over > 0 {
+		summary = fmt.Sprintf("%s, %d too many", summary, over)
+	}
+	return summary
+}
@@ -0,0 +1,139 @@
+package screens
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guysherman/tomato/timerview"
+)
+
+// ReflectionDoneMsg is sent when the reflection after a focus period has
+// been answered, or skipped. Rating is from 1 to 5, or 0 if none was given.
+type ReflectionDoneMsg struct {
+	Note    string
+	Rating  int
+	Skipped bool
+}
+
+// ReflectionKeyMap holds the key bindings for the reflection.
+type ReflectionKeyMap struct {
+	Next   key.Binding
+	Rate   key.Binding
+	Skip   key.Binding
+	Submit key.Binding
+}
+
+func DefaultReflectionKeyMap() ReflectionKeyMap {
+	return NewReflectionKeyMap(timerview.DefaultKeyMap())
+}
+
+// NewReflectionKeyMap takes the reflection's bindings from the app's key map.
+// The rating is always one of the keys 1 to 5.
+func NewReflectionKeyMap(k timerview.KeyMap) ReflectionKeyMap {
+	submit := k.Binding("reflection.next")
+	submit.SetHelp(submit.Help().Key, "Saves without a rating")
+	return ReflectionKeyMap{
+		Next:   k.Binding("reflection.next"),
+		Rate:   key.NewBinding(key.WithKeys("1", "2", "3", "4", "5"), key.WithHelp("1-5", "Rates your focus")),
+		Submit: submit,
+		Skip:   k.Binding("reflection.skip"),
+	}
+}
+
+// reflectionHelp shows the bindings for the step the reflection is on.
+type reflectionHelp struct {
+	keys   ReflectionKeyMap
+	rating bool
+}
+
+func (h reflectionHelp) ShortHelp() []key.Binding {
+	if h.rating {
+		return []key.Binding{h.keys.Rate, h.keys.Submit, h.keys.Skip}
+	}
+	return []key.Binding{h.keys.Next, h.keys.Skip}
+}
+
+func (h reflectionHelp) FullHelp() [][]key.Binding {
+	return [][]key.Binding{h.ShortHelp()}
+}
+
+// Reflection asks what got done in the focus period that just ended, and how
+// well it went, before moving on to the break.
+type Reflection struct {
+	input  textinput.Model
+	rating bool
+	keys   ReflectionKeyMap
+	help   help.Model
+}
+
+func NewReflection() Reflection {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "What did you get done?"
+	input.Focus()
+
+	return Reflection{
+		input: input,
+		keys:  DefaultReflectionKeyMap(),
+		help:  help.NewModel(),
+	}
+}
+
+// SetKeys rebinds the reflection's keys.
+func (m Reflection) SetKeys(keys ReflectionKeyMap) Reflection {
+	m.keys = keys
+	return m
+}
+
+func (m Reflection) Update(msg tea.Msg) (Reflection, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	if key.Matches(keyMsg, m.keys.Skip) {
+		return m, func() tea.Msg { return ReflectionDoneMsg{Skipped: true} }
+	}
+
+	note := strings.TrimSpace(m.input.Value())
+	if m.rating {
+		switch {
+		case key.Matches(keyMsg, m.keys.Rate):
+			rating := int(keyMsg.Runes[0] - '0')
+			return m, func() tea.Msg { return ReflectionDoneMsg{Note: note, Rating: rating} }
+		case key.Matches(keyMsg, m.keys.Submit):
+			return m, func() tea.Msg { return ReflectionDoneMsg{Note: note} }
+		}
+		return m, nil
+	}
+
+	if key.Matches(keyMsg, m.keys.Next) {
+		m.rating = true
+		m.input.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m Reflection) View() string {
+	title := lipgloss.NewStyle().Bold(true)
+	lines := []string{
+		title.Render("Tomato complete! What did you get done?"),
+		m.input.View(),
+		"",
+	}
+	if m.rating {
+		lines = append(lines, title.Render("How well did you focus, from 1 to 5?"), "")
+	}
+	lines = append(lines, m.help.View(reflectionHelp{keys: m.keys, rating: m.rating}))
+	return screenStyle.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,6 @@
+// Package screens holds the screens shown alongside the timer in the tab bar.
+package screens
+
+import "github.com/charmbracelet/lipgloss"
+
+var screenStyle = lipgloss.NewStyle().Padding(1, 2)
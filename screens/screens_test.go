@@ -0,0 +1,345 @@
+package screens
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/guysherman/tomato/goals"
+	"github.com/guysherman/tomato/history"
+	"github.com/guysherman/tomato/plan"
+	"github.com/guysherman/tomato/tasks"
+	"github.com/guysherman/tomato/theme"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func keyMsg(k string) tea.KeyMsg {
+	switch k {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+func TestScreens(t *testing.T) {
+	Convey("Screens", t, func() {
+		Convey("Tabs", func() {
+			Convey("wrap around", func() {
+				So(TimerTab.Next(), ShouldEqual, PlanTab)
+				So(SettingsTab.Next(), ShouldEqual, TimerTab)
+				So(TimerTab.Prev(), ShouldEqual, SettingsTab)
+			})
+
+			Convey("are all named in the tab bar", func() {
+				bar := TabBar(StatsTab, 80, theme.Default().Focus)
+				for _, tab := range Tabs {
+					So(bar, ShouldContainSubstring, tab.String())
+				}
+			})
+		})
+
+		Convey("Tasks", func() {
+			store := tasks.Store{Path: filepath.Join(t.TempDir(), "tasks.json")}
+			m := NewTasks(store, []tasks.Task{{Name: "Write tests"}, {Name: "Refactor parser"}}, "")
+
+			Convey("a adds a task and saves the list", func() {
+				m, _ = m.Update(keyMsg("a"))
+				So(m.Editing(), ShouldBeTrue)
+				m, _ = m.Update(keyMsg("Review PR"))
+				m, _ = m.Update(keyMsg("enter"))
+
+				So(m.Editing(), ShouldBeFalse)
+				So(m.List()[2].Name, ShouldEqual, "Review PR")
+				saved, _ := store.Load()
+				So(saved, ShouldResemble, m.List())
+			})
+
+			Convey("esc cancels adding a task", func() {
+				m, _ = m.Update(keyMsg("a"))
+				m, _ = m.Update(keyMsg("Review PR"))
+				m, _ = m.Update(keyMsg("esc"))
+				So(m.Editing(), ShouldBeFalse)
+				So(m.List(), ShouldHaveLength, 2)
+			})
+
+			Convey("enter picks the task under the cursor", func() {
+				m, _ = m.Update(keyMsg("j"))
+				m, cmd := m.Update(keyMsg("enter"))
+				So(cmd(), ShouldResemble, TaskSelectedMsg{Name: "Refactor parser"})
+				So(m.View(), ShouldContainSubstring, "🍅")
+			})
+
+			Convey("x ticks off the task", func() {
+				m, _ = m.Update(keyMsg("x"))
+				So(m.List()[0].Done, ShouldBeTrue)
+				So(m.View(), ShouldContainSubstring, "[x] Write tests")
+			})
+
+			Convey("d deletes the task", func() {
+				m, _ = m.Update(keyMsg("j"))
+				m, _ = m.Update(keyMsg("d"))
+				So(m.List(), ShouldResemble, []tasks.Task{{Name: "Write tests"}})
+				m, _ = m.Update(keyMsg("d"))
+				So(m.List(), ShouldBeEmpty)
+				So(m.View(), ShouldContainSubstring, "No tasks yet")
+			})
+		})
+
+		Convey("Plan", func() {
+			dir := t.TempDir()
+			store := plan.Store{Path: filepath.Join(dir, "plan.json")}
+			m := NewPlan(store, plan.Plan{Date: "2026-10-19", Items: []plan.Item{
+				{Task: "Refactor parser", Estimate: 2},
+				{Task: "Write tests", Estimate: 1},
+			}})
+
+			Convey("a adds a task with an estimate and saves the plan", func() {
+				m, _ = m.Update(keyMsg("a"))
+				So(m.Editing(), ShouldBeTrue)
+				for _, r := range "Review PRs" {
+					m, _ = m.Update(keyMsg(string(r)))
+				}
+				m, _ = m.Update(keyMsg("enter"))
+				m, _ = m.Update(keyMsg("3"))
+				m, cmd := m.Update(keyMsg("enter"))
+
+				So(m.Editing(), ShouldBeFalse)
+				So(cmd().(PlanChangedMsg).Plan.Items[2], ShouldResemble, plan.Item{Task: "Review PRs", Estimate: 3})
+				saved, err := store.Load("2026-10-19")
+				So(err, ShouldBeNil)
+				So(saved.Items, ShouldHaveLength, 3)
+			})
+
+			Convey("estimates must be whole numbers", func() {
+				m, _ = m.Update(keyMsg("a"))
+				m, _ = m.Update(keyMsg("x"))
+				m, _ = m.Update(keyMsg("enter"))
+				m, _ = m.Update(keyMsg("0"))
+				m, cmd := m.Update(keyMsg("enter"))
+				So(cmd, ShouldBeNil)
+				So(m.Editing(), ShouldBeTrue)
+				So(m.View(), ShouldContainSubstring, "whole number")
+			})
+
+			Convey("tasks can be reordered and re-estimated", func() {
+				m, _ = m.Update(keyMsg("J"))
+				m, _ = m.Update(keyMsg("+"))
+				So(m.Plan().Items[1], ShouldResemble, plan.Item{Task: "Refactor parser", Estimate: 3})
+				So(m.Plan().Current(), ShouldEqual, 0)
+			})
+
+			Convey("shows the plan against the time available", func() {
+				m = m.SetAvailable(Availability{Tomatos: 2, Until: time.Date(2026, 10, 19, 17, 0, 0, 0, time.UTC), Focus: 25 * time.Minute})
+				So(m.View(), ShouldContainSubstring, "3 of 3 🍅 left (1h15m of focus), room for 2 before 17:00, 1 too many")
+			})
+
+			Convey("credits tomatos to the task", func() {
+				m = m.Credit("Refactor parser")
+				So(m.Plan().Items[0].Spent, ShouldEqual, 1)
+				saved, _ := store.Load("2026-10-19")
+				So(saved.Items[0].Spent, ShouldEqual, 1)
+			})
+		})
+
+		Convey("Stats", func() {
+			now = func() time.Time { return time.Date(2026, 10, 21, 12, 0, 0, 0, time.UTC) }
+			Reset(func() { now = time.Now })
+
+			focus := func(start time.Time, task string) history.Record {
+				return history.Record{Start: start, Phase: "focus", Task: task, Outcome: history.Completed, Actual: 25 * time.Minute}
+			}
+			m := NewStats([]history.Record{
+				focus(time.Date(2026, 10, 21, 9, 0, 0, 0, time.UTC), "Refactor parser"),
+				focus(time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC), "Refactor parser"),
+				focus(time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC), ""),
+				{Start: time.Date(2026, 10, 21, 10, 0, 0, 0, time.UTC), Phase: "focus", Outcome: history.Stopped},
+				{Start: time.Date(2026, 10, 21, 10, 0, 0, 0, time.UTC), Phase: "short break", Outcome: history.Completed},
+			})
+
+			Convey("totals completed focus periods", func() {
+				view := m.View()
+				So(view, ShouldContainSubstring, "Today           1 🍅     0h25m focused")
+				So(view, ShouldContainSubstring, "This week       2 🍅     0h50m focused")
+				So(view, ShouldContainSubstring, "All time        3 🍅     1h15m focused")
+				So(view, ShouldContainSubstring, "Refactor parser")
+				So(view, ShouldContainSubstring, "(no task)")
+			})
+
+			Convey("updates as periods are added", func() {
+				m = m.Add(focus(time.Date(2026, 10, 21, 11, 0, 0, 0, time.UTC), "Review PR"))
+				So(m.View(), ShouldContainSubstring, "Today           2 🍅     0h50m focused")
+			})
+
+			Convey("charts tomatos per day, by calendar and by hour", func() {
+				view := m.View()
+				So(view, ShouldContainSubstring, "Tomatos per day, last 30 days")
+				So(view, ShouldContainSubstring, "Calendar")
+				So(view, ShouldContainSubstring, "Time of day")
+			})
+
+			Convey("totals tags", func() {
+				m = m.Add(history.Record{Start: now(), Phase: "focus", Task: "Fix login #acme", Tags: []string{"acme"}, Outcome: history.Completed, Actual: 25 * time.Minute})
+				So(m.View(), ShouldContainSubstring, "#acme")
+			})
+
+			Convey("scrolls with j and k", func() {
+				m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 10})
+				So(m.View(), ShouldContainSubstring, "Today")
+				m, _ = m.Update(keyMsg("j"))
+				So(m.View(), ShouldNotContainSubstring, "Today")
+				m, _ = m.Update(keyMsg("k"))
+				So(m.View(), ShouldContainSubstring, "Today")
+			})
+
+			Convey("shows notes and ratings", func() {
+				m = m.Annotate(time.Date(2026, 10, 21, 9, 0, 0, 0, time.UTC), "Split the lexer out", 4)
+				m = m.Annotate(time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC), "", 2)
+				So(m.View(), ShouldContainSubstring, "3.0 / 5 from 2 rated")
+				So(m.View(), ShouldContainSubstring, "★★★★☆  Split the lexer out")
+			})
+
+			Convey("totals projects, against their budgets", func() {
+				m = m.Add(history.Record{Start: time.Date(2026, 10, 21, 11, 0, 0, 0, time.UTC), Phase: "focus", Project: "acme", Outcome: history.Completed, Actual: 25 * time.Minute})
+				m = m.SetBudgets(goals.Budgets{"acme": 20 * time.Minute})
+				So(m.View(), ShouldContainSubstring, "Projects")
+				So(m.View(), ShouldContainSubstring, "(no project)")
+				So(m.View(), ShouldContainSubstring, "0h25m of 0h20m  over budget!")
+			})
+		})
+
+		Convey("Charts", func() {
+			Convey("bar charts scale to the biggest value", func() {
+				So(barChart([]int{0, 1, 2}, 2, 1), ShouldResemble, []string{
+					"2 │    █",
+					"0 │  █ █",
+				})
+			})
+
+			Convey("sparklines scale to the biggest value", func() {
+				So(sparkline([]int{0, 1, 4, 8}), ShouldEqual, " ▁▄█")
+			})
+
+			Convey("heatmaps shade each day of each week", func() {
+				wednesday := time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC)
+				counts := map[string]int{"2026-10-12": 4, "2026-10-20": 1}
+				So(heatmap(counts, wednesday, 2), ShouldResemble, []string{
+					"Mon █·",
+					"    ·░",
+					"Wed ··",
+					"    ·",
+					"Fri ·",
+					"    ·",
+					"Sun ·",
+				})
+			})
+		})
+
+		Convey("Settings", func() {
+			checkNumber := func(s string) error {
+				if strings.Trim(s, "0123456789") != "" {
+					return errors.New("not a number")
+				}
+				return nil
+			}
+			m := NewSettings([]Setting{
+				{Key: "long_break_tomatos", Label: "Tomatos per long break", Value: "4", Validate: checkNumber},
+				{Label: "Config file", Value: "/home/me/.config/tomato/config.json"},
+			})
+
+			Convey("lists each setting", func() {
+				So(m.View(), ShouldContainSubstring, "Tomatos per long break")
+				So(m.View(), ShouldContainSubstring, "config.json")
+			})
+
+			Convey("enter edits the setting, and sends the change", func() {
+				m, _ = m.Update(keyMsg("enter"))
+				So(m.Editing(), ShouldBeTrue)
+				m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+				m, _ = m.Update(keyMsg("3"))
+				m, cmd := m.Update(keyMsg("enter"))
+
+				So(m.Editing(), ShouldBeFalse)
+				So(cmd(), ShouldResemble, SettingChangedMsg{Key: "long_break_tomatos", Value: "3"})
+				So(m.Saved(nil).View(), ShouldContainSubstring, "Saved")
+			})
+
+			Convey("invalid values are reported, and editing carries on", func() {
+				m, _ = m.Update(keyMsg("enter"))
+				m, _ = m.Update(keyMsg("x"))
+				m, cmd := m.Update(keyMsg("enter"))
+
+				So(cmd, ShouldBeNil)
+				So(m.Editing(), ShouldBeTrue)
+				So(m.View(), ShouldContainSubstring, "not a number")
+			})
+
+			Convey("esc cancels the edit", func() {
+				m, _ = m.Update(keyMsg("enter"))
+				m, _ = m.Update(keyMsg("esc"))
+				So(m.Editing(), ShouldBeFalse)
+			})
+
+			Convey("settings without a Validate func can't be edited", func() {
+				m, _ = m.Update(keyMsg("j"))
+				m, _ = m.Update(keyMsg("enter"))
+				So(m.Editing(), ShouldBeFalse)
+			})
+		})
+
+		Convey("Label", func() {
+			Convey("starts with the current project and tags", func() {
+				m := NewLabel("Acme Corp", []string{"review"})
+				So(m.View(), ShouldContainSubstring, "Acme Corp #review")
+			})
+
+			Convey("enter sets them", func() {
+				m := NewLabel("", nil)
+				for _, r := range "acme #bug" {
+					m, _ = m.Update(keyMsg(string(r)))
+				}
+				_, cmd := m.Update(keyMsg("enter"))
+				So(cmd(), ShouldResemble, LabelledMsg{Project: "acme", Tags: []string{"bug"}})
+			})
+
+			Convey("esc cancels", func() {
+				_, cmd := NewLabel("", nil).Update(keyMsg("esc"))
+				So(cmd(), ShouldResemble, LabelledMsg{Cancelled: true})
+			})
+
+			Convey("the project is every word that isn't a tag", func() {
+				project, tags := ParseLabel("Acme #bug Corp #urgent")
+				So(project, ShouldEqual, "Acme Corp")
+				So(tags, ShouldResemble, []string{"bug", "urgent"})
+			})
+		})
+
+		Convey("Reflection", func() {
+			m := NewReflection()
+			m, _ = m.Update(keyMsg("Done"))
+
+			Convey("takes a note, then a rating", func() {
+				m, cmd := m.Update(keyMsg("enter"))
+				So(cmd, ShouldBeNil)
+				So(m.View(), ShouldContainSubstring, "1 to 5")
+				_, cmd = m.Update(keyMsg("4"))
+				So(cmd(), ShouldResemble, ReflectionDoneMsg{Note: "Done", Rating: 4})
+			})
+
+			Convey("the rating can be left out", func() {
+				m, _ = m.Update(keyMsg("enter"))
+				_, cmd := m.Update(keyMsg("enter"))
+				So(cmd(), ShouldResemble, ReflectionDoneMsg{Note: "Done"})
+			})
+
+			Convey("esc skips it", func() {
+				_, cmd := m.Update(keyMsg("esc"))
+				So(cmd(), ShouldResemble, ReflectionDoneMsg{Skipped: true})
+			})
+		})
+	})
+}
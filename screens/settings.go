@@ -0,0 +1,217 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guysherman/tomato/timerview"
+)
+
+// Setting is a labelled value shown on the settings screen. Settings with a
+// Validate func can be edited, and the others are only shown.
+type Setting struct {
+	Key      string
+	Label    string
+	Value    string
+	Validate func(string) error
+}
+
+// SettingChangedMsg is sent when a setting has been edited, with a value that
+// has passed its Validate func.
+type SettingChangedMsg struct {
+	Key   string
+	Value string
+}
+
+// SettingsKeyMap holds the key bindings for the settings screen.
+type SettingsKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Edit   key.Binding
+	Cancel key.Binding
+}
+
+func DefaultSettingsKeyMap() SettingsKeyMap {
+	return NewSettingsKeyMap(timerview.DefaultKeyMap())
+}
+
+// NewSettingsKeyMap takes the settings screen's bindings from the app's key
+// map.
+func NewSettingsKeyMap(k timerview.KeyMap) SettingsKeyMap {
+	return SettingsKeyMap{
+		Up:     k.Binding("settings.up"),
+		Down:   k.Binding("settings.down"),
+		Edit:   k.Binding("settings.edit"),
+		Cancel: k.Binding("settings.cancel"),
+	}
+}
+
+func (k SettingsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Edit, k.Cancel}
+}
+
+func (k SettingsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// Settings is the settings screen, a form with a field per setting.
+type Settings struct {
+	settings []Setting
+	cursor   int
+	editing  bool
+	input    textinput.Model
+	err      error
+	saved    bool
+	keys     SettingsKeyMap
+	help     help.Model
+	width    int
+	height   int
+}
+
+func NewSettings(settings []Setting) Settings {
+	input := textinput.New()
+	input.Prompt = ""
+
+	return Settings{
+		settings: settings,
+		input:    input,
+		keys:     DefaultSettingsKeyMap(),
+		help:     help.NewModel(),
+	}
+}
+
+// SetSettings replaces the values shown, keeping the cursor where it is.
+func (m Settings) SetSettings(settings []Setting) Settings {
+	m.settings = settings
+	if m.cursor >= len(settings) {
+		m.cursor = 0
+	}
+	return m
+}
+
+// SetKeys rebinds the screen's keys.
+func (m Settings) SetKeys(keys SettingsKeyMap) Settings {
+	m.keys = keys
+	return m
+}
+
+// Saved reports the outcome of saving a change, which is shown under the
+// form.
+func (m Settings) Saved(err error) Settings {
+	m.err = err
+	m.saved = err == nil
+	return m
+}
+
+// Editing reports whether a field is being edited, in which case every key
+// should go to the screen.
+func (m Settings) Editing() bool {
+	return m.editing
+}
+
+func (m Settings) Update(msg tea.Msg) (Settings, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		return m, nil
+	case tea.KeyMsg:
+		if m.editing {
+			return m.handleInput(msg)
+		}
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m Settings) handleKey(msg tea.KeyMsg) (Settings, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.cursor < len(m.settings)-1 {
+			m.cursor++
+		}
+	case key.Matches(msg, m.keys.Edit):
+		if len(m.settings) == 0 || m.settings[m.cursor].Validate == nil {
+			return m, nil
+		}
+		m.editing = true
+		m.err = nil
+		m.saved = false
+		m.input.SetValue(m.settings[m.cursor].Value)
+		m.input.CursorEnd()
+		return m, m.input.Focus()
+	}
+	return m, nil
+}
+
+func (m Settings) handleInput(msg tea.KeyMsg) (Settings, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Edit):
+		setting := m.settings[m.cursor]
+		value := strings.TrimSpace(m.input.Value())
+		if err := setting.Validate(value); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.editing = false
+		m.err = nil
+		m.input.Blur()
+		m.settings[m.cursor].Value = value
+		return m, func() tea.Msg { return SettingChangedMsg{Key: setting.Key, Value: value} }
+	case key.Matches(msg, m.keys.Cancel):
+		m.editing = false
+		m.err = nil
+		m.input.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m Settings) View() string {
+	labelWidth := 0
+	for _, s := range m.settings {
+		if len(s.Label) > labelWidth {
+			labelWidth = len(s.Label)
+		}
+	}
+
+	label := lipgloss.NewStyle().Bold(true)
+	faint := lipgloss.NewStyle().Faint(true)
+	lines := []string{}
+	for i, s := range m.settings {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		value := s.Value
+		if i == m.cursor && m.editing {
+			value = m.input.View()
+		} else if s.Validate == nil {
+			value = faint.Render(value)
+		}
+		lines = append(lines, fmt.Sprintf("%s%s  %s", cursor, label.Render(fmt.Sprintf("%-*s", labelWidth, s.Label)), value))
+	}
+
+	lines = append(lines, "")
+	switch {
+	case m.err != nil:
+		lines = append(lines, fmt.Sprintf("Error: %v", m.err))
+	case m.saved:
+		lines = append(lines, "Saved, changes apply from the next period.")
+	}
+	lines = append(lines, m.help.View(m.keys))
+	return screenStyle.Render(strings.Join(lines, "\n"))
+}
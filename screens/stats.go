@@ -0,0 +1,355 @@
+package screens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guysherman/tomato/duration"
+	"github.com/guysherman/tomato/goals"
+	"github.com/guysherman/tomato/history"
+	"github.com/guysherman/tomato/timerview"
+)
+
+// now is replaced in tests.
+var now = time.Now
+
+// Stats is the stats dashboard, drawn from the recorded history. It scrolls
+// when it doesn't fit the window.
+type Stats struct {
+	records []history.Record
+	budgets goals.Budgets
+	err     error
+	offset  int
+	keys    StatsKeyMap
+	help    help.Model
+	width   int
+	height  int
+}
+
+// StatsKeyMap holds the key bindings for the stats screen.
+type StatsKeyMap struct {
+	Up   key.Binding
+	Down key.Binding
+}
+
+func DefaultStatsKeyMap() StatsKeyMap {
+	return NewStatsKeyMap(timerview.DefaultKeyMap())
+}
+
+// NewStatsKeyMap takes the stats screen's bindings from the app's key map.
+func NewStatsKeyMap(k timerview.KeyMap) StatsKeyMap {
+	return StatsKeyMap{
+		Up:   k.Binding("stats.up"),
+		Down: k.Binding("stats.down"),
+	}
+}
+
+func (k StatsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down}
+}
+
+func (k StatsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+func NewStats(records []history.Record) Stats {
+	return Stats{
+		records: records,
+		keys:    DefaultStatsKeyMap(),
+		help:    help.NewModel(),
+	}
+}
+
+// Add records another period, so the dashboard stays up to date.
+func (m Stats) Add(r history.Record) Stats {
+	m.records = append(m.records, r)
+	return m
+}
+
+// SetKeys rebinds the screen's keys.
+func (m Stats) SetKeys(keys StatsKeyMap) Stats {
+	m.keys = keys
+	return m
+}
+
+// SetBudgets sets the weekly budgets that each project's time is shown
+// against.
+func (m Stats) SetBudgets(budgets goals.Budgets) Stats {
+	m.budgets = budgets
+	return m
+}
+
+// SetError shows a problem reading or writing the history above the stats, or
+// clears it when err is nil.
+func (m Stats) SetError(err error) Stats {
+	m.err = err
+	return m
+}
+
+// Records returns the history the stats are worked out from.
+func (m Stats) Records() []history.Record {
+	return m.records
+}
+
+// Annotate adds a note and rating to the record that started at start.
+func (m Stats) Annotate(start time.Time, note string, rating int) Stats {
+	for i := range m.records {
+		if m.records[i].Start.Equal(start) {
+			m.records[i].Note = note
+			m.records[i].Rating = rating
+		}
+	}
+	return m
+}
+
+func (m Stats) Update(msg tea.Msg) (Stats, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			if m.offset > 0 {
+				m.offset--
+			}
+		case key.Matches(msg, m.keys.Down):
+			if m.offset < len(m.lines())-1 {
+				m.offset++
+			}
+		}
+	}
+	return m, nil
+}
+
+// total sums up the completed focus periods.
+type total struct {
+	name     string
+	tomatoes int
+	focused  time.Duration
+}
+
+func (t total) add(r history.Record) total {
+	t.tomatoes++
+	t.focused += r.Actual
+	return t
+}
+
+func (t total) View() string {
+	return fmt.Sprintf("%-12s %4d 🍅  %8s focused", t.name, t.tomatoes, duration.FormatHours(t.focused))
+}
+
+// chartDays is the number of days in the tomatos per day chart.
+const chartDays = 30
+
+// chartHeight is the number of lines in the tomatos per day chart.
+const chartHeight = 6
+
+// maxHeatmapWeeks is the number of weeks in the calendar, when there's room.
+const maxHeatmapWeeks = 26
+
+func (m Stats) View() string {
+	lines := m.lines()
+	offset := m.offset
+	if offset > len(lines)-1 {
+		offset = len(lines) - 1
+	}
+	lines = lines[offset:]
+	if m.err != nil {
+		lines = append([]string{fmt.Sprintf("Error: %v", m.err), ""}, lines...)
+	}
+
+	// Leave room for the padding and the help.
+	if room := m.height - 4; m.height > 0 && len(lines) > room && room > 0 {
+		lines = lines[:room]
+	}
+	lines = append(lines, "", m.help.View(m.keys))
+	return screenStyle.Render(strings.Join(lines, "\n"))
+}
+
+// lines draws the whole dashboard, before it's scrolled.
+func (m Stats) lines() []string {
+	loc := now().Location()
+	today := startOfDay(now())
+	week := history.StartOfWeek(today)
+	first := today.AddDate(0, 0, -(chartDays - 1))
+
+	totals := []total{{name: "Today"}, {name: "This week"}, {name: "All time"}}
+	byProject := map[string]total{}
+	byTask := map[string]total{}
+	byTag := map[string]total{}
+	byDay := map[string]int{}
+	byHour := make([]int, 24)
+	for _, r := range m.records {
+		if !r.IsFocus() || r.Outcome != history.Completed {
+			continue
+		}
+		start := r.Start.In(loc)
+		if !start.Before(today) {
+			totals[0] = totals[0].add(r)
+		}
+		if !start.Before(week) {
+			totals[1] = totals[1].add(r)
+		}
+		totals[2] = totals[2].add(r)
+		byDay[history.DayKey(start)]++
+		byHour[start.Hour()]++
+
+		name := r.Task
+		if name == "" {
+			name = "(no task)"
+		}
+		byTask[name] = byTask[name].named(name).add(r)
+		project := r.Project
+		if project == "" {
+			project = "(no project)"
+		}
+		byProject[project] = byProject[project].named(project).add(r)
+		for _, tag := range r.Tags {
+			byTag[tag] = byTag[tag].named("#" + tag).add(r)
+		}
+	}
+
+	title := lipgloss.NewStyle().Bold(true)
+	lines := []string{}
+	for _, t := range totals {
+		lines = append(lines, t.View())
+	}
+	if rating, rated := averageRating(m.records); rated > 0 {
+		lines = append(lines, fmt.Sprintf("%-12s %6.1f / 5 from %d rated", "Focus rating", rating, rated))
+	}
+
+	perDay := make([]int, chartDays)
+	for i := range perDay {
+		perDay[i] = byDay[history.DayKey(first.AddDate(0, 0, i))]
+	}
+	// Mark the day of the month under every seventh bar, counting back from
+	// today, lining up with the bars past the scale.
+	axis := strings.Repeat(" ", len(fmt.Sprint(maxOf(perDay)))+2)
+	for i := range perDay {
+		if (chartDays-1-i)%7 == 0 {
+			axis += fmt.Sprintf("%-2d", first.AddDate(0, 0, i).Day())
+		} else {
+			axis += "  "
+		}
+	}
+	lines = append(lines, "", title.Render(fmt.Sprintf("Tomatos per day, last %d days", chartDays)))
+	lines = append(lines, barChart(perDay, chartHeight, 1)...)
+	lines = append(lines, strings.TrimRight(axis, " "))
+
+	weeks := maxHeatmapWeeks
+	if m.width > 0 && m.width-8 < weeks {
+		weeks = m.width - 8
+	}
+	if weeks > 0 {
+		lines = append(lines, "", title.Render("Calendar"))
+		lines = append(lines, heatmap(byDay, today, weeks)...)
+	}
+
+	lines = append(lines, "", title.Render("Time of day"))
+	lines = append(lines, sparkline(byHour), "0     6     12    18   23")
+
+	lines = append(lines, m.budgetLines(title)...)
+	lines = append(lines, totalsTable("Projects", byProject, title)...)
+	lines = append(lines, totalsTable("Tasks", byTask, title)...)
+	lines = append(lines, totalsTable("Tags", byTag, title)...)
+	lines = append(lines, m.notes(title, loc)...)
+	return lines
+}
+
+// budgetLines shows the time spent this week on each project with a budget.
+func (m Stats) budgetLines(title lipgloss.Style) []string {
+	if len(m.budgets) == 0 {
+		return nil
+	}
+	lines := []string{"", title.Render("Budgets this week")}
+	for _, b := range m.budgets.Measure(m.records, now()) {
+		line := fmt.Sprintf("  %-30s %8s of %s", timerview.Truncate(b.Project, 30), duration.FormatHours(b.Spent), duration.FormatHours(b.Budget))
+		if b.Over() {
+			line += "  over budget!"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// recentNotes is the number of notes shown on the dashboard.
+const recentNotes = 5
+
+// notes lists the latest reflections, newest first.
+func (m Stats) notes(title lipgloss.Style, loc *time.Location) []string {
+	lines := []string{}
+	for i := len(m.records) - 1; i >= 0 && len(lines) < recentNotes; i-- {
+		r := m.records[i]
+		if r.Note == "" && r.Rating == 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  %s  %s  %s", r.Start.In(loc).Format("Jan 02 15:04"), Stars(r.Rating), r.Note))
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return append([]string{"", title.Render("Recent notes")}, lines...)
+}
+
+// Stars draws a rating out of 5, or blanks if there isn't one.
+func Stars(rating int) string {
+	if rating < 1 {
+		return strings.Repeat(" ", 5)
+	}
+	return strings.Repeat("★", rating) + strings.Repeat("☆", 5-rating)
+}
+
+func averageRating(records []history.Record) (float64, int) {
+	sum, rated := 0, 0
+	for _, r := range records {
+		if r.Rating > 0 {
+			sum += r.Rating
+			rated++
+		}
+	}
+	if rated == 0 {
+		return 0, 0
+	}
+	return float64(sum) / float64(rated), rated
+}
+
+func (t total) named(name string) total {
+	t.name = name
+	return t
+}
+
+// totalsTable lists the totals, most time focused first.
+func totalsTable(heading string, totals map[string]total, title lipgloss.Style) []string {
+	if len(totals) == 0 {
+		return nil
+	}
+
+	sorted := []total{}
+	for _, t := range totals {
+		sorted = append(sorted, t)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].focused != sorted[j].focused {
+			return sorted[i].focused > sorted[j].focused
+		}
+		return sorted[i].name < sorted[j].name
+	})
+
+	lines := []string{"", title.Render(heading)}
+	for _, t := range sorted {
+		lines = append(lines, fmt.Sprintf("  %-30s %4d 🍅  %8s", timerview.Truncate(t.name, 30), t.tomatoes, duration.FormatHours(t.focused)))
+	}
+	return lines
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,71 @@
+package screens
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guysherman/tomato/theme"
+)
+
+// Tab names one of the screens in the tab bar.
+type Tab int
+
+const (
+	TimerTab Tab = iota
+	PlanTab
+	TasksTab
+	StatsTab
+	SettingsTab
+)
+
+// Tabs lists the tabs in the order they're shown.
+var Tabs = []Tab{TimerTab, PlanTab, TasksTab, StatsTab, SettingsTab}
+
+func (t Tab) String() string {
+	switch t {
+	case PlanTab:
+		return "Plan"
+	case TasksTab:
+		return "Tasks"
+	case StatsTab:
+		return "Stats"
+	case SettingsTab:
+		return "Settings"
+	default:
+		return "Timer"
+	}
+}
+
+// Next is the tab to the right of t, wrapping around at the end.
+func (t Tab) Next() Tab {
+	return Tabs[(int(t)+1)%len(Tabs)]
+}
+
+// Prev is the tab to the left of t, wrapping around at the start.
+func (t Tab) Prev() Tab {
+	return Tabs[(int(t)+len(Tabs)-1)%len(Tabs)]
+}
+
+// TabBarHeight is the number of lines taken by the tab bar.
+const TabBarHeight = 1
+
+// TabBar draws the names of the tabs across the top of the screen, with the
+// active one highlighted.
+func TabBar(active Tab, width int, colors theme.Phase) string {
+	activeStyle := lipgloss.NewStyle().
+		Foreground(colors.ActiveText.Adaptive()).
+		Background(colors.ActiveButton.Adaptive()).
+		Padding(0, 1).
+		Reverse(theme.NoColor())
+	inactiveStyle := lipgloss.NewStyle().
+		Foreground(colors.Text.Adaptive()).
+		Padding(0, 1)
+
+	names := []string{}
+	for _, t := range Tabs {
+		style := inactiveStyle
+		if t == active {
+			style = activeStyle
+		}
+		names = append(names, style.Render(t.String()))
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(lipgloss.JoinHorizontal(lipgloss.Top, names...))
+}
@@ -0,0 +1,222 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guysherman/tomato/tasks"
+	"github.com/guysherman/tomato/timerview"
+)
+
+// TaskSelectedMsg is sent when a task is picked to work on.
+type TaskSelectedMsg struct {
+	Name string
+}
+
+// TaskKeyMap holds the key bindings for the tasks screen.
+type TaskKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Add    key.Binding
+	Select key.Binding
+	Done   key.Binding
+	Delete key.Binding
+}
+
+func DefaultTaskKeyMap() TaskKeyMap {
+	return NewTaskKeyMap(timerview.DefaultKeyMap())
+}
+
+// NewTaskKeyMap takes the tasks screen's bindings from the app's key map.
+func NewTaskKeyMap(k timerview.KeyMap) TaskKeyMap {
+	return TaskKeyMap{
+		Up:     k.Binding("tasks.up"),
+		Down:   k.Binding("tasks.down"),
+		Add:    k.Binding("tasks.add"),
+		Select: k.Binding("tasks.select"),
+		Done:   k.Binding("tasks.done"),
+		Delete: k.Binding("tasks.delete"),
+	}
+}
+
+func (k TaskKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Add, k.Select, k.Done, k.Delete}
+}
+
+func (k TaskKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// Tasks is the task list screen. Tasks can be added, ticked off and deleted,
+// and picking one makes it the task that the following focus periods are
+// credited to.
+type Tasks struct {
+	list    []tasks.Task
+	store   tasks.Store
+	cursor  int
+	current string
+	adding  bool
+	input   textinput.Model
+	keys    TaskKeyMap
+	help    help.Model
+	width   int
+	height  int
+	err     error
+}
+
+func NewTasks(store tasks.Store, list []tasks.Task, current string) Tasks {
+	input := textinput.New()
+	input.Placeholder = "What needs doing?"
+	input.Prompt = "New task: "
+
+	return Tasks{
+		list:    list,
+		store:   store,
+		current: current,
+		input:   input,
+		keys:    DefaultTaskKeyMap(),
+		help:    help.NewModel(),
+	}
+}
+
+// SetKeys rebinds the screen's keys.
+func (m Tasks) SetKeys(keys TaskKeyMap) Tasks {
+	m.keys = keys
+	return m
+}
+
+// Editing reports whether a task name is being typed, in which case every key
+// should go to the screen.
+func (m Tasks) Editing() bool {
+	return m.adding
+}
+
+// List returns the tasks, in order.
+func (m Tasks) List() []tasks.Task {
+	return m.list
+}
+
+func (m Tasks) Update(msg tea.Msg) (Tasks, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		return m, nil
+	case TaskSelectedMsg:
+		m.current = msg.Name
+		return m, nil
+	case tea.KeyMsg:
+		if m.adding {
+			return m.handleInput(msg)
+		}
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m Tasks) handleKey(msg tea.KeyMsg) (Tasks, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.cursor < len(m.list)-1 {
+			m.cursor++
+		}
+	case key.Matches(msg, m.keys.Add):
+		m.adding = true
+		m.input.SetValue("")
+		return m, m.input.Focus()
+	case key.Matches(msg, m.keys.Select):
+		if len(m.list) == 0 {
+			return m, nil
+		}
+		name := m.list[m.cursor].Name
+		m.current = name
+		return m, func() tea.Msg { return TaskSelectedMsg{Name: name} }
+	case key.Matches(msg, m.keys.Done):
+		if len(m.list) == 0 {
+			return m, nil
+		}
+		m.list[m.cursor].Done = !m.list[m.cursor].Done
+		m = m.save()
+	case key.Matches(msg, m.keys.Delete):
+		if len(m.list) == 0 {
+			return m, nil
+		}
+		m.list = append(m.list[:m.cursor:m.cursor], m.list[m.cursor+1:]...)
+		if m.cursor >= len(m.list) && m.cursor > 0 {
+			m.cursor--
+		}
+		m = m.save()
+	}
+	return m, nil
+}
+
+func (m Tasks) handleInput(msg tea.KeyMsg) (Tasks, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		name := strings.TrimSpace(m.input.Value())
+		m.adding = false
+		m.input.Blur()
+		if name == "" {
+			return m, nil
+		}
+		m.list = append(m.list, tasks.Task{Name: name})
+		m.cursor = len(m.list) - 1
+		return m.save(), nil
+	case tea.KeyEsc:
+		m.adding = false
+		m.input.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m Tasks) save() Tasks {
+	m.err = m.store.Save(m.list)
+	return m
+}
+
+func (m Tasks) View() string {
+	lines := []string{}
+	if len(m.list) == 0 {
+		lines = append(lines, "No tasks yet, press a to add one.")
+	}
+	for i, t := range m.list {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		check := "[ ]"
+		if t.Done {
+			check = "[x]"
+		}
+		line := fmt.Sprintf("%s%s %s", cursor, check, t.Name)
+		if t.Name == m.current {
+			line = lipgloss.NewStyle().Bold(true).Render(line + "  🍅")
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "")
+	if m.adding {
+		lines = append(lines, m.input.View())
+	} else {
+		lines = append(lines, m.help.View(m.keys))
+	}
+	if m.err != nil {
+		lines = append(lines, fmt.Sprintf("Error saving tasks: %v", m.err))
+	}
+	return screenStyle.Render(strings.Join(lines, "\n"))
+}
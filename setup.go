@@ -0,0 +1,566 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guysherman/tomato/bigdigits"
+	"github.com/guysherman/tomato/config"
+	"github.com/guysherman/tomato/duration"
+	"github.com/guysherman/tomato/forecast"
+	"github.com/guysherman/tomato/goals"
+	"github.com/guysherman/tomato/history"
+	"github.com/guysherman/tomato/journal"
+	"github.com/guysherman/tomato/notifications"
+	"github.com/guysherman/tomato/plan"
+	"github.com/guysherman/tomato/screens"
+	"github.com/guysherman/tomato/tasks"
+	"github.com/guysherman/tomato/terminal"
+	"github.com/guysherman/tomato/theme"
+	"github.com/guysherman/tomato/timerview"
+)
+
+// options holds the command line flags. set records which flags were given,
+// as those take precedence over the config file.
+type options struct {
+	focusTime        string
+	shortBreakTime   string
+	longBreakTime    string
+	longBreakTomatos int
+	quietModeScript  string
+	noiseModeScript  string
+	task             string
+	project          string
+	tags             string
+	layout           string
+	inline           bool
+	plain            bool
+	configPath       string
+	set              map[string]bool
+}
+
+func parseOptions(name string, args []string) options {
+	s := options{}
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.StringVar(&s.focusTime, "f", "25m", "Sets the length of the focus period, as minutes, mm:ss or <number><unit> eg 25m")
+	flags.StringVar(&s.shortBreakTime, "s", "5m", "Sets the length of the short break, as minutes, mm:ss or <number><unit> eg 5m")
+	flags.StringVar(&s.longBreakTime, "l", "15m", "Sets the length of the long break, as minutes, mm:ss or <number><unit> eg 15m")
+	flags.IntVar(&s.longBreakTomatos, "L", 4, "Sets the number of tomatos per long break, expressed in <number> eg 4")
+	flags.StringVar(&s.quietModeScript, "q", "tomato_quiet.sh", "Sets the script to run when focus mode starts")
+	flags.StringVar(&s.noiseModeScript, "n", "tomato_noise.sh", "Sets the script to run when focus mode ends")
+	flags.StringVar(&s.task, "t", "", "Sets the name of the task being worked on")
+	flags.StringVar(&s.project, "p", "", "Sets the project or client the focus periods are for")
+	flags.StringVar(&s.tags, "tags", "", "Sets tags for the focus periods, separated by commas eg review,urgent")
+	flags.StringVar(&s.layout, "layout", "auto", "Sets the layout, one of auto, full, compact or mini")
+	flags.BoolVar(&s.inline, "inline", false, "Runs the timer in a couple of lines of the terminal, rather than taking over the screen")
+	flags.BoolVar(&s.plain, "plain", false, "Prints plain text events instead of running the interactive timer, which is the default when output is not a terminal")
+	flags.StringVar(&s.configPath, "c", "", "Sets the path of the config file (default <user config dir>/tomato/config.json)")
+	flags.Parse(args)
+
+	s.set = map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		s.set[f.Name] = true
+	})
+	return s
+}
+
+// timerConfig is the timer section of the config, overridden by any flags
+// that were given.
+func (s options) timerConfig(cfg config.Timer) config.Timer {
+	if s.set["f"] {
+		cfg.Focus = s.focusTime
+	}
+	if s.set["s"] {
+		cfg.ShortBreak = s.shortBreakTime
+	}
+	if s.set["l"] {
+		cfg.LongBreak = s.longBreakTime
+	}
+	if s.set["L"] {
+		cfg.LongBreakTomatos = s.longBreakTomatos
+	}
+	if s.set["q"] {
+		cfg.QuietModeScript = s.quietModeScript
+	}
+	if s.set["n"] {
+		cfg.NoiseModeScript = s.noiseModeScript
+	}
+	return cfg
+}
+
+// parseDuration parses one of the period lengths, which came from either the
+// flag or the config key.
+func (s options) parseDuration(flagName string, key string, value string) (time.Duration, error) {
+	d, err := duration.Parse(value)
+	if err != nil {
+		return 0, s.settingError(flagName, key, err)
+	}
+	return d, nil
+}
+
+// settingError names the flag, or the config key, that a bad value came from.
+func (s options) settingError(flagName string, key string, err error) error {
+	if s.set[flagName] {
+		return fmt.Errorf("reading -%s: %w", flagName, err)
+	}
+	return fmt.Errorf("reading %s in the config file: %w", key, err)
+}
+
+// newTomato builds the main model from the command line flags and the config
+// file, checking the config for mistakes along the way.
+func newTomato(s options) (Tomato, error) {
+	layout, err := timerview.ParseLayout(s.layout)
+	if err != nil {
+		return Tomato{}, fmt.Errorf("reading -layout: %w", err)
+	}
+
+	configPath := resolveConfigPath(s.configPath)
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return Tomato{}, fmt.Errorf("loading config: %w", err)
+	}
+
+	timer := s.timerConfig(cfg.Timer)
+	focusTime, err := s.parseDuration("f", "timer.focus", timer.Focus)
+	if err != nil {
+		return Tomato{}, err
+	}
+	shortBreakTime, err := s.parseDuration("s", "timer.short_break", timer.ShortBreak)
+	if err != nil {
+		return Tomato{}, err
+	}
+	longBreakTime, err := s.parseDuration("l", "timer.long_break", timer.LongBreak)
+	if err != nil {
+		return Tomato{}, err
+	}
+	if err := checkTomatos(strconv.Itoa(timer.LongBreakTomatos)); err != nil {
+		return Tomato{}, s.settingError("L", "timer.long_break_tomatos", err)
+	}
+
+	focusNotification, err := newTemplate(cfg.Notifications.Focus)
+	if err != nil {
+		return Tomato{}, fmt.Errorf("loading focus notification: %w", err)
+	}
+	shortBreakNotification, err := newTemplate(cfg.Notifications.ShortBreak)
+	if err != nil {
+		return Tomato{}, fmt.Errorf("loading short break notification: %w", err)
+	}
+	longBreakNotification, err := newTemplate(cfg.Notifications.LongBreak)
+	if err != nil {
+		return Tomato{}, fmt.Errorf("loading long break notification: %w", err)
+	}
+	goalNotification, err := newTemplate(cfg.Notifications.Goal)
+	if err != nil {
+		return Tomato{}, fmt.Errorf("loading goal notification: %w", err)
+	}
+	budgetNotification, err := newTemplate(cfg.Notifications.Budget)
+	if err != nil {
+		return Tomato{}, fmt.Errorf("loading budget notification: %w", err)
+	}
+
+	backend := notifications.Backend(cfg.Notifications.Backend)
+	if !backend.Valid() {
+		return Tomato{}, fmt.Errorf("loading config: unknown notification backend %q", backend)
+	}
+
+	if _, ok := bigdigits.Lookup(cfg.Display.Font); !ok && cfg.Display.Font != "small" {
+		return Tomato{}, fmt.Errorf("loading config: unknown font %q, expected small or one of %v", cfg.Display.Font, bigdigits.Names())
+	}
+
+	colors, ok := theme.Lookup(cfg.Theme, cfg.Themes)
+	if !ok {
+		return Tomato{}, fmt.Errorf("loading config: unknown theme %q, expected one of %v or a theme in themes", cfg.Theme, theme.Names())
+	}
+
+	keys, err := timerview.NewKeyMap(cfg.Keys)
+	if err != nil {
+		return Tomato{}, fmt.Errorf("loading config: %w", err)
+	}
+
+	boundaries, err := newBoundaries(cfg.Schedule)
+	if err != nil {
+		return Tomato{}, fmt.Errorf("loading schedule: %w", err)
+	}
+
+	dayStart, dayEnd, err := workingHours(cfg.Schedule)
+	if err != nil {
+		return Tomato{}, fmt.Errorf("loading schedule: %w", err)
+	}
+
+	targets, err := newGoals(cfg.Goals)
+	if err != nil {
+		return Tomato{}, fmt.Errorf("loading goals: %w", err)
+	}
+	budgets, err := newBudgets(cfg.Projects)
+	if err != nil {
+		return Tomato{}, fmt.Errorf("loading projects: %w", err)
+	}
+
+	warnings, err := newWarnings(cfg.Warnings, map[timerMode]time.Duration{
+		focus:      focusTime,
+		shortBreak: shortBreakTime,
+		longBreak:  longBreakTime,
+	})
+	if err != nil {
+		return Tomato{}, fmt.Errorf("loading warnings: %w", err)
+	}
+
+	output := terminal.NewOutput(os.Stdout)
+
+	var notes journal.Journal
+	if cfg.Journal.Format != "" {
+		notes, err = journal.New(journal.Format(cfg.Journal.Format), cfg.Journal.Path)
+		if err != nil {
+			return Tomato{}, fmt.Errorf("loading journal: %w", err)
+		}
+	}
+
+	dataDir, err := resolveDataDir(cfg)
+	if err != nil {
+		return Tomato{}, fmt.Errorf("finding data dir: %w", err)
+	}
+	// Unreadable lines in the history are left out, and shown on the stats
+	// screen, rather than stopping the timer from starting.
+	records, historyErr := historyStore(dataDir).Load()
+	var skipped *history.SkippedError
+	if historyErr != nil && !errors.As(historyErr, &skipped) {
+		return Tomato{}, fmt.Errorf("loading history: %w", historyErr)
+	}
+	planStore := plan.Store{Path: filepath.Join(dataDir, "plan.json")}
+	today, err := planStore.Load(currentDate())
+	if err != nil {
+		return Tomato{}, fmt.Errorf("loading plan: %w", err)
+	}
+	taskStore := tasks.Store{Path: filepath.Join(dataDir, "tasks.json")}
+	taskList, err := taskStore.Load()
+	if err != nil {
+		return Tomato{}, fmt.Errorf("loading tasks: %w", err)
+	}
+
+	m := Tomato{
+		mode:                   focus,
+		tomatoCount:            0,
+		currentWidth:           120,
+		currentHeight:          40,
+		focusTime:              focusTime,
+		shortBreakTime:         shortBreakTime,
+		longBreakTime:          longBreakTime,
+		longBreakTomatos:       timer.LongBreakTomatos,
+		quietModeScript:        timer.QuietModeScript,
+		noiseModeScript:        timer.NoiseModeScript,
+		task:                   s.task,
+		project:                strings.TrimSpace(s.project),
+		tags:                   splitTags(s.tags),
+		budgets:                budgets,
+		focusNotification:      focusNotification,
+		shortBreakNotification: shortBreakNotification,
+		longBreakNotification:  longBreakNotification,
+		goalNotification:       goalNotification,
+		budgetNotification:     budgetNotification,
+		output:                 output,
+		notifier:               notifications.Notifier{Backend: backend, Output: output.Send},
+		warnings:               warnings,
+		reporter:               terminal.Reporter{Title: cfg.Terminal.Title, Progress: cfg.Terminal.Progress, Output: output.Send},
+		font:                   cfg.Display.Font,
+		boundaries:             boundaries,
+		dayStart:               dayStart,
+		dayEnd:                 dayEnd,
+		theme:                  colors,
+		keys:                   keys,
+		layout:                 layout,
+		inline:                 s.inline,
+		history:                historyStore(dataDir),
+		journal:                notes,
+		reflect:                cfg.Reflection,
+		goals:                  targets,
+		todayCount:             targets.Measure(records, time.Now()).Today,
+		today:                  currentDate(),
+		plan:                   screens.NewPlan(planStore, today).SetKeys(screens.NewPlanKeyMap(keys)),
+		tasks:                  screens.NewTasks(taskStore, taskList, s.task).SetKeys(screens.NewTaskKeyMap(keys)),
+		stats:                  screens.NewStats(records).SetBudgets(budgets).SetError(historyErr).SetKeys(screens.NewStatsKeyMap(keys)),
+		configPath:             configPath,
+		dataDir:                dataDir,
+		config:                 cfg,
+	}
+	if m.inline {
+		m.layout = timerview.InlineLayout
+	}
+	if m.task == "" {
+		m = m.followPlan()
+	}
+	m.currentView = m.viewForMode()
+	m.settings = screens.NewSettings(m.settingsRows()).SetKeys(screens.NewSettingsKeyMap(keys))
+	return m, nil
+}
+
+// resolveConfigPath falls back to the default config path when -c isn't
+// given. It's empty if there's no default either.
+func resolveConfigPath(path string) string {
+	if path != "" {
+		return path
+	}
+	path, err := config.DefaultPath()
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+func loadConfig(path string) (config.Config, error) {
+	if path == "" {
+		return config.Default(), nil
+	}
+	return config.Load(path)
+}
+
+// resolveDataDir falls back to the default data dir when the config doesn't
+// name one.
+func resolveDataDir(cfg config.Config) (string, error) {
+	if cfg.DataDir != "" {
+		return cfg.DataDir, nil
+	}
+	return config.DefaultDataDir()
+}
+
+// newTemplate builds a notification template, checking that it renders so that
+// mistakes show up at startup rather than when the period ends.
+func newTemplate(m config.MessageTemplate) (notifications.Template, error) {
+	tmpl, err := notifications.NewTemplate(m.Title, m.Body, m.MessagesFile)
+	if err != nil {
+		return tmpl, err
+	}
+	for _, body := range append([]string{tmpl.Body}, tmpl.Messages...) {
+		check := notifications.Template{Title: tmpl.Title, Body: body}
+		if _, _, err := check.Render(notifications.Vars{}); err != nil {
+			return tmpl, err
+		}
+	}
+	return tmpl, nil
+}
+
+// newWarnings checks the configured warnings against the lengths of the periods
+// they apply to, since a warning with Before as long as its period would never
+// be sent.
+func newWarnings(configured []config.Warning, lengths map[timerMode]time.Duration) ([]warning, error) {
+	warnings := []warning{}
+	for i, w := range configured {
+		before, err := duration.Parse(w.Before)
+		if err != nil {
+			return nil, fmt.Errorf("reading warnings[%d].before: %w", i, err)
+		}
+		tmpl, err := newTemplate(config.MessageTemplate{Title: w.Title, Body: w.Body})
+		if err != nil {
+			return nil, err
+		}
+
+		warning := warning{phase: w.Phase, before: before, template: tmpl, hook: w.Hook}
+		if !warning.appliesTo(focus) && !warning.appliesTo(shortBreak) && !warning.appliesTo(longBreak) {
+			return nil, fmt.Errorf("unknown phase %q", w.Phase)
+		}
+		for _, mode := range []timerMode{focus, shortBreak, longBreak} {
+			if warning.appliesTo(mode) && before >= lengths[mode] {
+				return nil, fmt.Errorf("reading warnings[%d].before: %s is not less than the %s length of %s",
+					i, duration.Format(before), mode, duration.Format(lengths[mode]))
+			}
+		}
+		warnings = append(warnings, warning)
+	}
+	return warnings, nil
+}
+
+func newBoundaries(schedule config.Schedule) ([]time.Duration, error) {
+	clocks := schedule.Boundaries
+	if schedule.DayEnd != "" {
+		clocks = append([]string{schedule.DayEnd}, clocks...)
+	}
+
+	boundaries := []time.Duration{}
+	for _, c := range clocks {
+		b, err := forecast.ParseClock(c)
+		if err != nil {
+			return nil, err
+		}
+		boundaries = append(boundaries, b)
+	}
+	return boundaries, nil
+}
+
+func newGoals(c config.Goals) (goals.Goals, error) {
+	if c.DailyTomatos < 0 {
+		return goals.Goals{}, fmt.Errorf("daily_tomatos can't be negative, got %d", c.DailyTomatos)
+	}
+	if c.WeeklyHours < 0 {
+		return goals.Goals{}, fmt.Errorf("weekly_hours can't be negative, got %v", c.WeeklyHours)
+	}
+	weekly := time.Duration(c.WeeklyHours * float64(time.Hour)).Round(time.Minute)
+	return goals.Goals{Daily: c.DailyTomatos, Weekly: weekly}, nil
+}
+
+func newBudgets(projects map[string]config.Project) (goals.Budgets, error) {
+	budgets := goals.Budgets{}
+	for name, p := range projects {
+		if p.WeeklyHours < 0 {
+			return nil, fmt.Errorf("%s.weekly_hours can't be negative, got %v", name, p.WeeklyHours)
+		}
+		if p.WeeklyHours > 0 {
+			budgets[name] = time.Duration(p.WeeklyHours * float64(time.Hour)).Round(time.Minute)
+		}
+	}
+	return budgets, nil
+}
+
+// splitTags reads the tags given with -tags, allowing for spaces and #s.
+func splitTags(s string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// workingHours parses the start and end of the working day. Either is zero
+// if it isn't set.
+func workingHours(schedule config.Schedule) (time.Duration, time.Duration, error) {
+	hours := []time.Duration{0, 0}
+	for i, c := range []string{schedule.DayStart, schedule.DayEnd} {
+		if c == "" {
+			continue
+		}
+		h, err := forecast.ParseClock(c)
+		if err != nil {
+			return 0, 0, err
+		}
+		hours[i] = h
+	}
+	return hours[0], hours[1], nil
+}
+
+// settingsRows lists the settings shown on the settings screen, along with
+// how to check edits to them.
+func (m Tomato) settingsRows() []screens.Setting {
+	backend := string(m.notifier.Backend)
+	if backend == "" {
+		backend = string(notifications.Kitty)
+	}
+	anything := func(string) error { return nil }
+
+	return []screens.Setting{
+		{Key: "focus", Label: "Focus", Value: duration.Format(m.focusTime), Validate: m.checkLength(focus)},
+		{Key: "short_break", Label: "Short break", Value: duration.Format(m.shortBreakTime), Validate: m.checkLength(shortBreak)},
+		{Key: "long_break", Label: "Long break", Value: duration.Format(m.longBreakTime), Validate: m.checkLength(longBreak)},
+		{Key: "long_break_tomatos", Label: "Tomatos per long break", Value: strconv.Itoa(m.longBreakTomatos), Validate: checkTomatos},
+		{Key: "quiet_mode_script", Label: "Quiet mode script", Value: m.quietModeScript, Validate: anything},
+		{Key: "noise_mode_script", Label: "Noise mode script", Value: m.noiseModeScript, Validate: anything},
+		{Key: "backend", Label: "Notifications", Value: backend, Validate: checkBackend},
+		{Key: "theme", Label: "Theme", Value: m.config.Theme, Validate: m.checkTheme},
+		{Label: "Config file", Value: m.configPath},
+		{Label: "Data dir", Value: m.dataDir},
+	}
+}
+
+// checkLength returns the check for edits to the length of a mode's periods,
+// which also makes sure the configured warnings still fit in them, as they
+// are checked at startup.
+func (m Tomato) checkLength(mode timerMode) func(string) error {
+	return func(s string) error {
+		d, err := duration.Parse(s)
+		if err != nil {
+			return err
+		}
+		lengths := map[timerMode]time.Duration{
+			focus:      m.focusTime,
+			shortBreak: m.shortBreakTime,
+			longBreak:  m.longBreakTime,
+		}
+		lengths[mode] = d
+		_, err = newWarnings(m.config.Warnings, lengths)
+		return err
+	}
+}
+
+func checkTomatos(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return fmt.Errorf("expected a whole number of at least 1, not %q", s)
+	}
+	return nil
+}
+
+func checkBackend(s string) error {
+	if !notifications.Backend(s).Valid() {
+		return fmt.Errorf("unknown notification backend %q, expected kitty, notify-send or none", s)
+	}
+	return nil
+}
+
+func (m Tomato) checkTheme(s string) error {
+	if _, ok := theme.Lookup(s, m.config.Themes); !ok {
+		return fmt.Errorf("unknown theme %q, expected one of %v or a theme in themes", s, theme.Names())
+	}
+	return nil
+}
+
+// applySetting changes a setting edited on the settings screen, both in the
+// model, from the next period onward, and in the config to be saved. The value
+// has already been checked.
+func (m Tomato) applySetting(key string, value string) Tomato {
+	switch key {
+	case "focus":
+		m.focusTime, _ = duration.Parse(value)
+		m.config.Timer.Focus = duration.Format(m.focusTime)
+	case "short_break":
+		m.shortBreakTime, _ = duration.Parse(value)
+		m.config.Timer.ShortBreak = duration.Format(m.shortBreakTime)
+	case "long_break":
+		m.longBreakTime, _ = duration.Parse(value)
+		m.config.Timer.LongBreak = duration.Format(m.longBreakTime)
+	case "long_break_tomatos":
+		m.longBreakTomatos, _ = strconv.Atoi(value)
+		m.config.Timer.LongBreakTomatos = m.longBreakTomatos
+	case "quiet_mode_script":
+		m.quietModeScript = value
+		m.config.Timer.QuietModeScript = value
+	case "noise_mode_script":
+		m.noiseModeScript = value
+		m.config.Timer.NoiseModeScript = value
+	case "backend":
+		m.notifier.Backend = notifications.Backend(value)
+		m.config.Notifications.Backend = value
+	case "theme":
+		m.theme, _ = theme.Lookup(value, m.config.Themes)
+		m.config.Theme = value
+	}
+	return m
+}
+
+// configSetting is where an edited setting goes in the config file, and the
+// value it's saved as.
+func (m Tomato) configSetting(key string) (string, interface{}) {
+	switch key {
+	case "focus":
+		return "timer.focus", m.config.Timer.Focus
+	case "short_break":
+		return "timer.short_break", m.config.Timer.ShortBreak
+	case "long_break":
+		return "timer.long_break", m.config.Timer.LongBreak
+	case "long_break_tomatos":
+		return "timer.long_break_tomatos", m.config.Timer.LongBreakTomatos
+	case "quiet_mode_script":
+		return "timer.quiet_mode_script", m.config.Timer.QuietModeScript
+	case "noise_mode_script":
+		return "timer.noise_mode_script", m.config.Timer.NoiseModeScript
+	case "backend":
+		return "notifications.backend", m.config.Notifications.Backend
+	default:
+		return "theme", m.config.Theme
+	}
+}
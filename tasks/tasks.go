@@ -0,0 +1,57 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Task is an entry in the task list.
+type Task struct {
+	Name string `json:"name"`
+	Done bool   `json:"done,omitempty"`
+}
+
+// Store keeps the task list in a JSON file. A Store with no Path keeps
+// nothing.
+type Store struct {
+	Path string
+}
+
+// Load reads the task list. A missing file is an empty list.
+func (s Store) Load() ([]Task, error) {
+	list := []Task{}
+	if s.Path == "" {
+		return list, nil
+	}
+
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return list, nil
+	} else if err != nil {
+		return list, err
+	}
+
+	if err := json.Unmarshal(data, &list); err != nil {
+		return list, err
+	}
+	return list, nil
+}
+
+// Save replaces the task list in the file.
+func (s Store) Save(list []Task) error {
+	if s.Path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path, data, 0644)
+}
@@ -0,0 +1,37 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTasks(t *testing.T) {
+	Convey("Tasks", t, func() {
+		store := Store{Path: filepath.Join(t.TempDir(), "tomato", "tasks.json")}
+
+		Convey("Missing file is an empty list", func() {
+			list, err := store.Load()
+			So(err, ShouldBeNil)
+			So(list, ShouldBeEmpty)
+		})
+
+		Convey("Saved lists are loaded back", func() {
+			list := []Task{{Name: "Write tests"}, {Name: "Refactor parser", Done: true}}
+			So(store.Save(list), ShouldBeNil)
+
+			loaded, err := store.Load()
+			So(err, ShouldBeNil)
+			So(loaded, ShouldResemble, list)
+		})
+
+		Convey("Malformed file is an error", func() {
+			os.MkdirAll(filepath.Dir(store.Path), 0755)
+			os.WriteFile(store.Path, []byte("["), 0644)
+			_, err := store.Load()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
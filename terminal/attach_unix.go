@@ -0,0 +1,32 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package terminal
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// Attach does for a program drawing to o what bubbletea does when it draws to
+// an *os.File: it calls resized with the size of the terminal, and again each
+// time the terminal is resized, until done is closed.
+func (o *Output) Attach(done <-chan struct{}, resized func(width int, height int)) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGWINCH)
+	defer signal.Stop(sig)
+
+	for {
+		if width, height, err := term.GetSize(int(o.File.Fd())); err == nil {
+			resized(width, height)
+		}
+		select {
+		case <-done:
+			return
+		case <-sig:
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build windows
+// +build windows
+
+package terminal
+
+import (
+	"github.com/muesli/termenv"
+	"golang.org/x/term"
+)
+
+// Attach does for a program drawing to o what bubbletea does when it draws to
+// an *os.File: it turns on escape sequences in the console, and calls resized
+// with the size of the terminal. Windows doesn't signal resizes, so that's
+// only done once.
+func (o *Output) Attach(done <-chan struct{}, resized func(width int, height int)) {
+	termenv.EnableWindowsANSIConsole()
+	if width, height, err := term.GetSize(int(o.File.Fd())); err == nil {
+		resized(width, height)
+	}
+	<-done
+}
@@ -0,0 +1,30 @@
+package terminal
+
+import (
+	"os"
+	"sync"
+)
+
+// Output is the terminal a program draws to. Writes to it are serialised, so
+// the escape sequences sent from commands, for the title, progress and
+// notifications, land between the program's frames rather than in them.
+type Output struct {
+	File *os.File
+	mu   sync.Mutex
+}
+
+func NewOutput(f *os.File) *Output {
+	return &Output{File: f}
+}
+
+func (o *Output) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.File.Write(p)
+}
+
+// Send writes an escape sequence. It suits Reporter.Output, and the Output of
+// a notifications.Notifier.
+func (o *Output) Send(s string) {
+	o.Write([]byte(s))
+}
@@ -0,0 +1,98 @@
+package terminal
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ProgressState is the state reported in a ConEmu/Windows Terminal OSC 9;4 sequence.
+type ProgressState int
+
+const (
+	ProgressNone ProgressState = iota
+	ProgressNormal
+	ProgressError
+	ProgressIndeterminate
+	ProgressPaused
+)
+
+type SendEscapeSequence func(string)
+
+// Reporter reports timer state in the terminal's window title and taskbar progress.
+type Reporter struct {
+	Title    bool
+	Progress bool
+	Output   SendEscapeSequence
+}
+
+// Save pushes the current window title onto the terminal's title stack, so
+// that Restore can put it back.
+func (r Reporter) Save() {
+	if r.Title {
+		r.send(PushTitleSequence())
+	}
+}
+
+func (r Reporter) Restore() {
+	if r.Title {
+		r.send(PopTitleSequence())
+	}
+	if r.Progress {
+		r.send(ProgressSequence(ProgressNone, 0))
+	}
+}
+
+func (r Reporter) Report(title string, state ProgressState, percent float64) {
+	if r.Title {
+		r.send(TitleSequence(title))
+	}
+	if r.Progress {
+		r.send(ProgressSequence(state, int(math.Round(percent*100))))
+	}
+}
+
+func (r Reporter) send(s string) {
+	if r.Output == nil {
+		fmt.Print(s)
+	} else {
+		r.Output(s)
+	}
+}
+
+// TitleSequence sets both the icon name and the window title (OSC 0).
+func TitleSequence(title string) string {
+	return fmt.Sprintf("\x1b]0;%s\x07", title)
+}
+
+func PushTitleSequence() string {
+	return "\x1b[22;0t"
+}
+
+func PopTitleSequence() string {
+	return "\x1b[23;0t"
+}
+
+func ProgressSequence(state ProgressState, percent int) string {
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+	return fmt.Sprintf("\x1b]9;4;%d;%d\x07", state, percent)
+}
+
+// FormatClock formats a duration as mm:ss, or h:mm:ss when it is an hour or more.
+func FormatClock(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d < 0 {
+		d = 0
+	}
+	h := int(d / time.Hour)
+	m := int(d%time.Hour) / int(time.Minute)
+	s := int(d%time.Minute) / int(time.Second)
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
@@ -0,0 +1,74 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTerminal(t *testing.T) {
+	Convey("Reporter", t, func() {
+		sequences := []string{}
+		output := func(s string) { sequences = append(sequences, s) }
+
+		Convey("Reports title and progress", func() {
+			r := Reporter{Title: true, Progress: true, Output: output}
+			r.Report("🍅 12:34 Focus", ProgressNormal, 0.5)
+			So(sequences, ShouldResemble, []string{"\x1b]0;🍅 12:34 Focus\x07", "\x1b]9;4;1;50\x07"})
+		})
+
+		Convey("Sends nothing when disabled", func() {
+			r := Reporter{Output: output}
+			r.Save()
+			r.Report("title", ProgressNormal, 0.5)
+			r.Restore()
+			So(sequences, ShouldBeEmpty)
+		})
+
+		Convey("Restore pops the title and clears progress", func() {
+			r := Reporter{Title: true, Progress: true, Output: output}
+			r.Restore()
+			So(sequences, ShouldResemble, []string{"\x1b[23;0t", "\x1b]9;4;0;0\x07"})
+		})
+	})
+
+	Convey("Output", t, func() {
+		f, err := os.Create(filepath.Join(t.TempDir(), "tty"))
+		So(err, ShouldBeNil)
+		defer f.Close()
+		out := NewOutput(f)
+
+		Convey("Writes frames and sequences whole, one after another", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 50; i++ {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					fmt.Fprint(out, "[frame]")
+				}()
+				go func() {
+					defer wg.Done()
+					Reporter{Title: true, Output: out.Send}.Report("title", ProgressNormal, 0)
+				}()
+			}
+			wg.Wait()
+
+			data, err := os.ReadFile(f.Name())
+			So(err, ShouldBeNil)
+			rest := strings.NewReplacer("[frame]", "", TitleSequence("title"), "").Replace(string(data))
+			So(rest, ShouldBeEmpty)
+		})
+	})
+
+	Convey("FormatClock", t, func() {
+		So(FormatClock(12*time.Minute+34*time.Second), ShouldEqual, "12:34")
+		So(FormatClock(time.Hour+5*time.Second), ShouldEqual, "1:00:05")
+		So(FormatClock(-time.Second), ShouldEqual, "00:00")
+	})
+}
@@ -0,0 +1,201 @@
+package theme
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Color is a colour that can differ between light and dark terminals. In JSON
+// it is either a single colour, eg "#FF0000" or "1", or an object with "light"
+// and "dark" colours.
+type Color lipgloss.AdaptiveColor
+
+func (c Color) Adaptive() lipgloss.AdaptiveColor {
+	return lipgloss.AdaptiveColor(c)
+}
+
+// Resolve picks the light or dark colour for the current terminal, for things
+// like the progress bar that don't take adaptive colours.
+func (c Color) Resolve() string {
+	if lipgloss.HasDarkBackground() {
+		return c.Dark
+	}
+	return c.Light
+}
+
+func (c Color) IsZero() bool {
+	return c.Light == "" && c.Dark == ""
+}
+
+func (c *Color) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*c = Color{Light: single, Dark: single}
+		return nil
+	}
+
+	var adaptive struct {
+		Light string `json:"light"`
+		Dark  string `json:"dark"`
+	}
+	if err := json.Unmarshal(data, &adaptive); err != nil {
+		return err
+	}
+	*c = Color{Light: adaptive.Light, Dark: adaptive.Dark}
+	return nil
+}
+
+func (c Color) MarshalJSON() ([]byte, error) {
+	if c.Light == c.Dark {
+		return json.Marshal(c.Light)
+	}
+	return json.Marshal(map[string]string{"light": c.Light, "dark": c.Dark})
+}
+
+// Phase is the set of colours used while a focus period or a break is running.
+type Phase struct {
+	Button        Color `json:"button"`
+	ButtonText    Color `json:"button_text"`
+	ActiveButton  Color `json:"active_button"`
+	ActiveText    Color `json:"active_button_text"`
+	Border        Color `json:"border"`
+	ProgressStart Color `json:"progress_start"`
+	ProgressEnd   Color `json:"progress_end"`
+	ProgressEmpty Color `json:"progress_empty"`
+	Text          Color `json:"text"`
+}
+
+type Theme struct {
+	Focus Phase `json:"focus"`
+	Break Phase `json:"break"`
+}
+
+var builtins = map[string]Theme{
+	"default": {
+		Focus: Phase{
+			Button:        Color{Light: "7", Dark: "7"},
+			ButtonText:    Color{Light: "8", Dark: "8"},
+			ActiveButton:  Color{Light: "1", Dark: "1"},
+			ActiveText:    Color{Light: "255", Dark: "255"},
+			Border:        Color{Light: "1", Dark: "1"},
+			ProgressStart: Color{Light: "#CC0000", Dark: "#FF0000"},
+			ProgressEnd:   Color{Light: "#CC0000", Dark: "#FF0000"},
+			ProgressEmpty: Color{Light: "#C0C0C0", Dark: "#606060"},
+		},
+		Break: Phase{
+			Button:        Color{Light: "7", Dark: "7"},
+			ButtonText:    Color{Light: "8", Dark: "8"},
+			ActiveButton:  Color{Light: "2", Dark: "2"},
+			ActiveText:    Color{Light: "8", Dark: "8"},
+			Border:        Color{Light: "2", Dark: "2"},
+			ProgressStart: Color{Light: "#00AA00", Dark: "#00FF00"},
+			ProgressEnd:   Color{Light: "#00AA00", Dark: "#00FF00"},
+			ProgressEmpty: Color{Light: "#C0C0C0", Dark: "#606060"},
+		},
+	},
+	"solarized": {
+		Focus: Phase{
+			Button:        Color{Light: "#EEE8D5", Dark: "#073642"},
+			ButtonText:    Color{Light: "#657B83", Dark: "#93A1A1"},
+			ActiveButton:  Color{Light: "#DC322F", Dark: "#DC322F"},
+			ActiveText:    Color{Light: "#FDF6E3", Dark: "#FDF6E3"},
+			Border:        Color{Light: "#DC322F", Dark: "#DC322F"},
+			ProgressStart: Color{Light: "#CB4B16", Dark: "#CB4B16"},
+			ProgressEnd:   Color{Light: "#DC322F", Dark: "#DC322F"},
+			ProgressEmpty: Color{Light: "#EEE8D5", Dark: "#073642"},
+			Text:          Color{Light: "#586E75", Dark: "#93A1A1"},
+		},
+		Break: Phase{
+			Button:        Color{Light: "#EEE8D5", Dark: "#073642"},
+			ButtonText:    Color{Light: "#657B83", Dark: "#93A1A1"},
+			ActiveButton:  Color{Light: "#859900", Dark: "#859900"},
+			ActiveText:    Color{Light: "#FDF6E3", Dark: "#FDF6E3"},
+			Border:        Color{Light: "#859900", Dark: "#859900"},
+			ProgressStart: Color{Light: "#2AA198", Dark: "#2AA198"},
+			ProgressEnd:   Color{Light: "#859900", Dark: "#859900"},
+			ProgressEmpty: Color{Light: "#EEE8D5", Dark: "#073642"},
+			Text:          Color{Light: "#586E75", Dark: "#93A1A1"},
+		},
+	},
+	"high-contrast": {
+		Focus: Phase{
+			Button:        Color{Light: "#FFFFFF", Dark: "#000000"},
+			ButtonText:    Color{Light: "#000000", Dark: "#FFFFFF"},
+			ActiveButton:  Color{Light: "#000000", Dark: "#FFFF00"},
+			ActiveText:    Color{Light: "#FFFF00", Dark: "#000000"},
+			Border:        Color{Light: "#000000", Dark: "#FFFFFF"},
+			ProgressStart: Color{Light: "#000000", Dark: "#FFFF00"},
+			ProgressEnd:   Color{Light: "#000000", Dark: "#FFFF00"},
+			ProgressEmpty: Color{Light: "#FFFFFF", Dark: "#000000"},
+			Text:          Color{Light: "#000000", Dark: "#FFFFFF"},
+		},
+		Break: Phase{
+			Button:        Color{Light: "#FFFFFF", Dark: "#000000"},
+			ButtonText:    Color{Light: "#000000", Dark: "#FFFFFF"},
+			ActiveButton:  Color{Light: "#000000", Dark: "#00FFFF"},
+			ActiveText:    Color{Light: "#00FFFF", Dark: "#000000"},
+			Border:        Color{Light: "#000000", Dark: "#FFFFFF"},
+			ProgressStart: Color{Light: "#000000", Dark: "#00FFFF"},
+			ProgressEnd:   Color{Light: "#000000", Dark: "#00FFFF"},
+			ProgressEmpty: Color{Light: "#FFFFFF", Dark: "#000000"},
+			Text:          Color{Light: "#000000", Dark: "#FFFFFF"},
+		},
+	},
+}
+
+func Default() Theme {
+	return builtins["default"]
+}
+
+// Lookup finds a theme by name, in the user's themes first and then in the
+// built-in ones. Colours a user theme leaves out are taken from the default.
+func Lookup(name string, user map[string]Theme) (Theme, bool) {
+	if t, ok := user[name]; ok {
+		return t.withDefaults(Default()), true
+	}
+	t, ok := builtins[name]
+	return t, ok
+}
+
+// Names lists the built-in themes.
+func Names() []string {
+	names := []string{}
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NoColor reports whether colour is turned off, eg by NO_COLOR being set.
+func NoColor() bool {
+	return lipgloss.ColorProfile() == termenv.Ascii
+}
+
+func (t Theme) withDefaults(base Theme) Theme {
+	return Theme{
+		Focus: t.Focus.withDefaults(base.Focus),
+		Break: t.Break.withDefaults(base.Break),
+	}
+}
+
+func (p Phase) withDefaults(base Phase) Phase {
+	fill := func(c *Color, b Color) {
+		if c.IsZero() {
+			*c = b
+		}
+	}
+	fill(&p.Button, base.Button)
+	fill(&p.ButtonText, base.ButtonText)
+	fill(&p.ActiveButton, base.ActiveButton)
+	fill(&p.ActiveText, base.ActiveText)
+	fill(&p.Border, base.Border)
+	fill(&p.ProgressStart, base.ProgressStart)
+	fill(&p.ProgressEnd, p.ProgressStart)
+	fill(&p.ProgressEmpty, base.ProgressEmpty)
+	fill(&p.Text, base.Text)
+	return p
+}
@@ -0,0 +1,58 @@
+package theme
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTheme(t *testing.T) {
+	Convey("Color", t, func() {
+		Convey("A single colour is used for light and dark", func() {
+			var c Color
+			So(json.Unmarshal([]byte(`"#FF0000"`), &c), ShouldBeNil)
+			So(c, ShouldResemble, Color{Light: "#FF0000", Dark: "#FF0000"})
+		})
+
+		Convey("An object gives separate light and dark colours", func() {
+			var c Color
+			So(json.Unmarshal([]byte(`{"light": "#000000", "dark": "#FFFFFF"}`), &c), ShouldBeNil)
+			So(c, ShouldResemble, Color{Light: "#000000", Dark: "#FFFFFF"})
+		})
+
+		Convey("Round trips through JSON", func() {
+			c := Color{Light: "#000000", Dark: "#FFFFFF"}
+			data, _ := json.Marshal(c)
+			var back Color
+			So(json.Unmarshal(data, &back), ShouldBeNil)
+			So(back, ShouldResemble, c)
+		})
+	})
+
+	Convey("Lookup", t, func() {
+		Convey("Finds built-in themes", func() {
+			for _, name := range Names() {
+				_, ok := Lookup(name, nil)
+				So(ok, ShouldBeTrue)
+			}
+		})
+
+		Convey("Fills in colours a user theme leaves out", func() {
+			user := map[string]Theme{
+				"mine": {Focus: Phase{ProgressStart: Color{Light: "#111111", Dark: "#111111"}}},
+			}
+			mine, ok := Lookup("mine", user)
+			So(ok, ShouldBeTrue)
+			So(mine.Focus.ProgressStart, ShouldResemble, Color{Light: "#111111", Dark: "#111111"})
+			So(mine.Focus.ProgressEnd, ShouldResemble, mine.Focus.ProgressStart)
+			So(mine.Focus.Border, ShouldResemble, Default().Focus.Border)
+			So(mine.Break, ShouldResemble, Default().Break)
+		})
+
+		Convey("Unknown themes are not found", func() {
+			_, ok := Lookup("nope", nil)
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
@@ -1,37 +1,45 @@
 package timerview
 
 import (
-	"fmt"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/guysherman/tomato/notifications"
+	"github.com/guysherman/tomato/theme"
 )
 
-func NewBreakMode(duration string, interval time.Duration, width int, height int) TimerView {
+func NewBreakMode(duration time.Duration, interval time.Duration, width int, height int, opts ModeOptions) TimerView {
+	colors := opts.Colors
+	if colors == (theme.Phase{}) {
+		colors = theme.Default().Break
+	}
+
 	inactiveButtonStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8")).
-		Background(lipgloss.Color("7")).
+		Foreground(colors.ButtonText.Adaptive()).
+		Background(colors.Button.Adaptive()).
 		Padding(0, 3).
 		Margin(1)
 
 	activeButtonStyle := inactiveButtonStyle.Copy().
-		Foreground(lipgloss.Color("8")).
-		Background(lipgloss.Color("2")).
+		Foreground(colors.ActiveText.Adaptive()).
+		Background(colors.ActiveButton.Adaptive()).
 		Margin(1).
-		Underline(true)
+		Underline(true).
+		Reverse(theme.NoColor())
 
 	border := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("2")).
+		BorderForeground(colors.Border.Adaptive()).
 		Padding(2, 2, 0)
 
 	timerViewStyle := TimerViewStyle{
 		inactiveButtonStyle: inactiveButtonStyle,
 		activeButtonStyle:   activeButtonStyle,
 		borderStyle:         border,
-		progressBarColor:    "#00FF00",
+		progressBarStart:    colors.ProgressStart.Resolve(),
+		progressBarEnd:      colors.ProgressEnd.Resolve(),
+		progressBarEmpty:    colors.ProgressEmpty.Resolve(),
+		textStyle:           lipgloss.NewStyle().Foreground(colors.Text.Adaptive()),
 		startText:           "Start",
 		pauseText:           "Pause",
 		resumeText:          "Resume",
@@ -40,16 +48,23 @@ func NewBreakMode(duration string, interval time.Duration, width int, height int
 		width:               width,
 		height:              height,
 		onStop: func(m TimerView) (tea.Model, tea.Cmd) {
-			return m, focusComplete
+			return m, focusComplete(m, true)
 		},
 		onTimeout: func() {
-			n := notifications.NewNotification(
-				"Break Complete!",
-				"Hey you! Time to knuckle down.",
-				notifications.Focus,
-				func(s string) { fmt.Print(s) })
-			n.Send()
+			opts.Notifier.Notify(opts.NotificationTitle, opts.NotificationBody)
+		},
+		onWarning: func(w Warning) {
+			sendWarning(opts.Notifier, w)
 		},
+		warnings:  opts.Warnings,
+		phaseName: phaseName(opts.Phase, "Break"),
+		reporter:  opts.Reporter,
+		font:      opts.Font,
+		cycle:     opts.Cycle,
+		goals:     opts.Goals,
+		forecast:  opts.Forecast,
+		keys:      opts.Keys,
+		layout:    opts.Layout,
 	}
 
 	return NewTimerView(duration, interval, timerViewStyle)
@@ -0,0 +1,38 @@
+package timerview
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Cycle describes where a period sits in the run of tomatoes leading up to
+// the long break.
+type Cycle struct {
+	Completed int
+	Voided    int
+	Length    int
+	Today     int
+	NextPhase string
+}
+
+const (
+	filledTomato = "●"
+	emptyTomato  = "○"
+	voidedTomato = "✕"
+)
+
+func (c Cycle) View() string {
+	if c.Length <= 0 {
+		return ""
+	}
+
+	empty := c.Length - c.Completed - c.Voided
+	if empty < 0 {
+		empty = 0
+	}
+	tomatoes := strings.Repeat(filledTomato, c.Completed) +
+		strings.Repeat(voidedTomato, c.Voided) +
+		strings.Repeat(emptyTomato, empty)
+
+	return fmt.Sprintf("%s   %d today   next: %s", tomatoes, c.Today, c.NextPhase)
+}
@@ -1,37 +1,45 @@
 package timerview
 
 import (
-	"fmt"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/guysherman/tomato/notifications"
+	"github.com/guysherman/tomato/theme"
 )
 
-func NewFocusMode(duration string, interval time.Duration, width int, height int, noiseModeScript string, quietModeScript string) TimerView {
+func NewFocusMode(duration time.Duration, interval time.Duration, width int, height int, opts ModeOptions) TimerView {
+	colors := opts.Colors
+	if colors == (theme.Phase{}) {
+		colors = theme.Default().Focus
+	}
+
 	inactiveButtonStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8")).
-		Background(lipgloss.Color("7")).
+		Foreground(colors.ButtonText.Adaptive()).
+		Background(colors.Button.Adaptive()).
 		Padding(0, 3).
 		Margin(1)
 
 	activeButtonStyle := inactiveButtonStyle.Copy().
-		Foreground(lipgloss.Color("255")).
-		Background(lipgloss.Color("1")).
+		Foreground(colors.ActiveText.Adaptive()).
+		Background(colors.ActiveButton.Adaptive()).
 		Margin(1).
-		Underline(true)
+		Underline(true).
+		Reverse(theme.NoColor())
 
 	border := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("1")).
+		BorderForeground(colors.Border.Adaptive()).
 		Padding(2, 2, 0)
 
 	timerViewStyle := TimerViewStyle{
 		inactiveButtonStyle: inactiveButtonStyle,
 		activeButtonStyle:   activeButtonStyle,
 		borderStyle:         border,
-		progressBarColor:    "#FF0000",
+		progressBarStart:    colors.ProgressStart.Resolve(),
+		progressBarEnd:      colors.ProgressEnd.Resolve(),
+		progressBarEmpty:    colors.ProgressEmpty.Resolve(),
+		textStyle:           lipgloss.NewStyle().Foreground(colors.Text.Adaptive()),
 		startText:           "Start",
 		pauseText:           "Pause",
 		resumeText:          "Resume",
@@ -40,20 +48,30 @@ func NewFocusMode(duration string, interval time.Duration, width int, height int
 		width:               width,
 		height:              height,
 		onStop: func(m TimerView) (tea.Model, tea.Cmd) {
-			runScript(noiseModeScript)
-			return stopTimer(m)
+			model, cmd := stopTimer(m)
+			noise := perform(func() { RunScript(opts.NoiseModeScript) })
+			return model, tea.Sequentially(noise, cmd)
 		},
 		onTimeout: func() {
-			runScript(noiseModeScript)
-			n := notifications.NewNotification(
-				"Tomato Complete!",
-				"Well done! Another tomato down.",
-				notifications.Focus,
-				func(s string) { fmt.Print(s) })
-			n.Send()
+			RunScript(opts.NoiseModeScript)
+			opts.Notifier.Notify(opts.NotificationTitle, opts.NotificationBody)
+		},
+		onWarning: func(w Warning) {
+			sendWarning(opts.Notifier, w)
 		},
+		warnings:  opts.Warnings,
+		phaseName: phaseName(opts.Phase, "Focus"),
+		reporter:  opts.Reporter,
+		font:      opts.Font,
+		cycle:     opts.Cycle,
+		goals:     opts.Goals,
+		task:      opts.Task,
+		project:   opts.Project,
+		forecast:  opts.Forecast,
+		keys:      opts.Keys,
+		layout:    opts.Layout,
 		onStart: func() {
-			runScript(quietModeScript)
+			RunScript(opts.QuietModeScript)
 		},
 	}
 
@@ -0,0 +1,293 @@
+package timerview
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// KeyMap holds the key bindings for the timer view. The same bindings drive
+// both the dispatch of key presses and the help text.
+type KeyMap struct {
+	Start   key.Binding
+	Pause   key.Binding
+	Stop    key.Binding
+	Left    key.Binding
+	Right   key.Binding
+	Select  key.Binding
+	Help    key.Binding
+	Quit    key.Binding
+	NextTab key.Binding
+	PrevTab key.Binding
+	Label   key.Binding
+
+	bindings map[string]key.Binding
+}
+
+// KeyBindings maps action names to the keys bound to them, as found in the
+// config file, eg {"stop": ["x"], "quit": ["q", "ctrl+c"]}.
+type KeyBindings map[string][]string
+
+// action is something a key can be bound to: its name in the config file, the
+// keys bound to it by default, and what it does, for the help.
+type action struct {
+	name string
+	keys []string
+	desc string
+}
+
+// actionGroup is the actions for one screen, named with the screen as a
+// prefix, eg "tasks.add". A key can only be bound to one action on a screen,
+// but can do different things on different screens. The timer's actions have
+// no prefix.
+type actionGroup struct {
+	title   string
+	screen  string
+	actions []action
+}
+
+var actionGroups = []actionGroup{
+	{screen: "", actions: []action{
+		{"start_pause", []string{"space"}, "Starts or pauses the timer"},
+		{"stop", []string{"s"}, "Stops the timer"},
+		{"left", []string{"h", "left"}, "Selects the button to the left"},
+		{"right", []string{"l", "right"}, "Selects the button to the right"},
+		{"select", []string{"enter"}, "Presses the selected button"},
+		{"help", []string{"?"}, "Shows all the key bindings"},
+		{"quit", []string{"q"}, "Quits the application"},
+		{"next_tab", []string{"tab"}, "Shows the next screen"},
+		{"prev_tab", []string{"shift+tab"}, "Shows the previous screen"},
+		{"label", []string{"p"}, "Sets the project and tags"},
+	}},
+	{title: "Plan", screen: "plan", actions: []action{
+		{"plan.up", []string{"k", "up"}, "Moves up"},
+		{"plan.down", []string{"j", "down"}, "Moves down"},
+		{"plan.move_up", []string{"K"}, "Does the task sooner"},
+		{"plan.move_down", []string{"J"}, "Does the task later"},
+		{"plan.add", []string{"a"}, "Adds a task"},
+		{"plan.more", []string{"+", "="}, "Estimates a tomato more"},
+		{"plan.less", []string{"-"}, "Estimates a tomato less"},
+		{"plan.done", []string{"x"}, "Marks the task done"},
+		{"plan.delete", []string{"d"}, "Deletes the task"},
+	}},
+	{title: "Tasks", screen: "tasks", actions: []action{
+		{"tasks.up", []string{"k", "up"}, "Moves up"},
+		{"tasks.down", []string{"j", "down"}, "Moves down"},
+		{"tasks.add", []string{"a"}, "Adds a task"},
+		{"tasks.select", []string{"enter"}, "Works on the task"},
+		{"tasks.done", []string{"x"}, "Marks the task done"},
+		{"tasks.delete", []string{"d"}, "Deletes the task"},
+	}},
+	{title: "Stats", screen: "stats", actions: []action{
+		{"stats.up", []string{"k", "up"}, "Scrolls up"},
+		{"stats.down", []string{"j", "down"}, "Scrolls down"},
+	}},
+	{title: "Settings", screen: "settings", actions: []action{
+		{"settings.up", []string{"k", "up"}, "Moves up"},
+		{"settings.down", []string{"j", "down"}, "Moves down"},
+		{"settings.edit", []string{"enter"}, "Edits the setting"},
+		{"settings.cancel", []string{"esc"}, "Cancels the edit"},
+	}},
+	{title: "Reflection", screen: "reflection", actions: []action{
+		{"reflection.next", []string{"enter"}, "Moves on to the rating"},
+		{"reflection.skip", []string{"esc"}, "Skips the reflection"},
+	}},
+	{title: "Label", screen: "label", actions: []action{
+		{"label.save", []string{"enter"}, "Sets the project and tags"},
+		{"label.cancel", []string{"esc"}, "Leaves them as they were"},
+	}},
+}
+
+// tabActions work on every tab, so they can't share keys with the actions of
+// the screens on them.
+var tabActions = []string{"quit", "next_tab", "prev_tab"}
+
+// Actions lists the names that can be used in KeyBindings.
+var Actions = actionNames()
+
+func actionNames() []string {
+	names := []string{}
+	for _, g := range actionGroups {
+		for _, a := range g.actions {
+			names = append(names, a.name)
+		}
+	}
+	return names
+}
+
+func DefaultKeyMap() KeyMap {
+	k, _ := NewKeyMap(nil)
+	return k
+}
+
+// NewKeyMap builds a key map from the defaults, with any actions in overrides
+// rebound. It fails if an action is unknown or has no keys, or if a key is
+// bound to more than one action on the same screen.
+func NewKeyMap(overrides KeyBindings) (KeyMap, error) {
+	bindings := KeyBindings{}
+	descs := map[string]string{}
+	for _, g := range actionGroups {
+		for _, a := range g.actions {
+			bindings[a.name] = a.keys
+			descs[a.name] = a.desc
+		}
+	}
+	for action, keys := range overrides {
+		if _, ok := bindings[action]; !ok {
+			return KeyMap{}, fmt.Errorf("unknown key binding action %q, expected one of %v", action, Actions)
+		}
+		if len(keys) == 0 {
+			return KeyMap{}, fmt.Errorf("no keys bound to %q", action)
+		}
+		bindings[action] = keys
+	}
+
+	if err := checkConflicts(bindings); err != nil {
+		return KeyMap{}, err
+	}
+
+	k := KeyMap{bindings: map[string]key.Binding{}}
+	for action, keys := range bindings {
+		pressed := make([]string, len(keys))
+		for i, k := range keys {
+			if k == "space" {
+				k = tea.KeySpace.String()
+			}
+			pressed[i] = k
+		}
+		k.bindings[action] = key.NewBinding(
+			key.WithKeys(pressed...),
+			key.WithHelp(strings.Join(keys, "/"), descs[action]),
+		)
+	}
+	binding := func(action string, desc string) key.Binding {
+		b := k.Binding(action)
+		b.SetHelp(b.Help().Key, desc)
+		return b
+	}
+
+	k.Start = binding("start_pause", "Starts the timer")
+	k.Pause = binding("start_pause", "Pauses the timer")
+	k.Stop = k.Binding("stop")
+	k.Left = k.Binding("left")
+	k.Right = k.Binding("right")
+	k.Select = k.Binding("select")
+	k.Help = k.Binding("help")
+	k.Quit = k.Binding("quit")
+	k.NextTab = k.Binding("next_tab")
+	k.PrevTab = k.Binding("prev_tab")
+	k.Label = k.Binding("label")
+	return k, nil
+}
+
+// Binding is the binding for any of the Actions, including those of the other
+// screens, which build their own key maps from it. The zero KeyMap has the
+// default bindings.
+func (k KeyMap) Binding(action string) key.Binding {
+	if k.bindings == nil {
+		return DefaultKeyMap().Binding(action)
+	}
+	return k.bindings[action]
+}
+
+func checkConflicts(bindings KeyBindings) error {
+	conflicts := []string{}
+	for _, g := range actionGroups {
+		screen := KeyBindings{}
+		for _, a := range g.actions {
+			screen[a.name] = bindings[a.name]
+		}
+		if g.screen != "" && g.screen != "reflection" && g.screen != "label" {
+			for _, action := range tabActions {
+				screen[action] = bindings[action]
+			}
+		}
+		conflicts = append(conflicts, conflictsIn(screen)...)
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("conflicting key bindings: %s", strings.Join(conflicts, "; "))
+	}
+	return nil
+}
+
+// conflictsIn describes each key bound to more than one of the actions.
+func conflictsIn(bindings KeyBindings) []string {
+	actionsByKey := map[string][]string{}
+	for action, keys := range bindings {
+		for _, k := range keys {
+			actionsByKey[k] = append(actionsByKey[k], action)
+		}
+	}
+
+	conflicts := []string{}
+	for k, actions := range actionsByKey {
+		if len(actions) > 1 {
+			sort.Strings(actions)
+			conflicts = append(conflicts, fmt.Sprintf("%q is bound to %s", k, strings.Join(actions, " and ")))
+		}
+	}
+	return conflicts
+}
+
+func (k KeyMap) isZero() bool {
+	return len(k.Quit.Keys()) == 0
+}
+
+// ShortHelp returns the bindings shown in the help line under the buttons.
+// Together with FullHelp it implements help.KeyMap.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Start, k.Pause, k.Stop, k.Help, k.Quit}
+}
+
+// FullHelp returns the bindings grouped by category, in the same order as
+// HelpGroups.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	groups := [][]key.Binding{}
+	for _, g := range k.HelpGroups() {
+		groups = append(groups, g.Bindings)
+	}
+	return groups
+}
+
+// HelpGroup is a titled column of the full help.
+type HelpGroup struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+func (k KeyMap) HelpGroups() []HelpGroup {
+	start := k.Start
+	start.SetHelp(start.Help().Key, "Starts or pauses the timer")
+	start.SetEnabled(true)
+	stop := k.Stop
+	stop.SetEnabled(true)
+
+	groups := []HelpGroup{
+		{Title: "Timer", Bindings: []key.Binding{start, stop, k.Label}},
+		{Title: "Navigation", Bindings: []key.Binding{k.Left, k.Right, k.Select, k.NextTab, k.PrevTab}},
+		{Title: "App", Bindings: []key.Binding{k.Help, k.Quit}},
+	}
+	for _, g := range actionGroups[1:] {
+		bindings := []key.Binding{}
+		for _, a := range g.actions {
+			bindings = append(bindings, k.Binding(a.name))
+		}
+		groups = append(groups, HelpGroup{Title: g.title, Bindings: bindings})
+	}
+	return groups
+}
+
+// pressed reports whether msg is one of the binding's keys. Unlike key.Matches
+// it ignores whether the binding is enabled, as that only controls the help.
+func pressed(msg tea.KeyMsg, b key.Binding) bool {
+	for _, k := range b.Keys() {
+		if msg.String() == k {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package timerview
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guysherman/tomato/terminal"
+)
+
+// Layout is how much of the timer view is drawn, so that it fits small panes.
+type Layout int
+
+const (
+	// AutoLayout picks one of the others to suit the size of the window.
+	AutoLayout Layout = iota
+	FullLayout
+	// CompactLayout drops the help and the spacing around the buttons.
+	CompactLayout
+	// MiniLayout is a single line with the phase, time left and progress.
+	MiniLayout
+	// InlineLayout is the mini line with the short help under it, drawn at the
+	// left of the window rather than in the middle, for running outside the
+	// alternate screen.
+	InlineLayout
+)
+
+var layoutNames = map[string]Layout{
+	"auto":    AutoLayout,
+	"full":    FullLayout,
+	"compact": CompactLayout,
+	"mini":    MiniLayout,
+}
+
+func ParseLayout(s string) (Layout, error) {
+	if l, ok := layoutNames[s]; ok {
+		return l, nil
+	}
+	return AutoLayout, fmt.Errorf("unknown layout %q, expected auto, full, compact or mini", s)
+}
+
+// Smallest windows the full and compact layouts are used in.
+const (
+	fullWidth     = 70
+	fullHeight    = 22
+	compactWidth  = 36
+	compactHeight = 8
+)
+
+func chooseLayout(forced Layout, width int, height int) Layout {
+	if forced != AutoLayout {
+		return forced
+	}
+	if width >= fullWidth && height >= fullHeight {
+		return FullLayout
+	}
+	if width >= compactWidth && height >= compactHeight {
+		return CompactLayout
+	}
+	return MiniLayout
+}
+
+func progressWidth(l Layout, width int) int {
+	if l == CompactLayout {
+		return width - 4
+	}
+	return int(float64(width) * 0.64)
+}
+
+// frameStyle is the border drawn around the ui in the current layout.
+func (m TimerView) frameStyle() lipgloss.Style {
+	switch m.layout {
+	case CompactLayout:
+		return m.style.borderStyle.Copy().Padding(0, 1)
+	case MiniLayout, InlineLayout:
+		return lipgloss.NewStyle()
+	}
+	return m.style.borderStyle
+}
+
+// buttonStyles returns the active and inactive button styles in the current
+// layout.
+func (m TimerView) buttonStyles() (lipgloss.Style, lipgloss.Style) {
+	if m.layout == CompactLayout {
+		return m.style.activeButtonStyle.Copy().Margin(0, 1, 0, 0),
+			m.style.inactiveButtonStyle.Copy().Margin(0, 1, 0, 0)
+	}
+	return m.style.activeButtonStyle, m.style.inactiveButtonStyle
+}
+
+// miniView is the single line drawn in the mini layout.
+func (m TimerView) miniView() string {
+	text := fmt.Sprintf("%s %s", m.style.phaseName, terminal.FormatClock(m.timer.Timeout))
+	if m.started && !m.timer.Running() {
+		text += " paused"
+	}
+
+	bar := m.progressBar
+	bar.Width = m.style.width - lipgloss.Width(text) - 1
+	if bar.Width < 5 {
+		return text
+	}
+	return text + " " + bar.ViewAs(bar.Percent())
+}
+
+// inlineView is the mini line with the short help under it.
+func (m TimerView) inlineView() string {
+	return lipgloss.JoinVertical(lipgloss.Left, m.miniView(), m.help.View(m.keys))
+}
+
+// Truncate cuts s down to width columns, ending it with an ellipsis when
+// anything was cut.
+func Truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -1,3 +1,29 @@
 package timerview
 
-type TimerCompleteMsg struct{}
+import "time"
+
+// Period describes how a period went, so that it can be recorded. Elapsed is
+// the time the timer ran for, which leaves out time spent paused, and
+// Interruptions is the number of times it was paused.
+type Period struct {
+	Started       time.Time
+	Planned       time.Duration
+	Elapsed       time.Duration
+	Interruptions int
+}
+
+// TimerCompleteMsg is sent when a period runs out, or a break is skipped.
+type TimerCompleteMsg struct {
+	Period
+	Skipped bool
+}
+
+// TimerVoidedMsg is sent when a timer that had been started is stopped before
+// it ran out.
+type TimerVoidedMsg struct {
+	Period
+}
+
+// StartMsg starts the timer, as though the start button had been pressed. It
+// does nothing if the timer has already started.
+type StartMsg struct{}
@@ -0,0 +1,147 @@
+package timerview
+
+import (
+	"math"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// nudgeStep is how much a turn of the mouse wheel over the progress bar adds
+// to, or takes off, the timer.
+const nudgeStep = time.Minute
+
+type zone struct {
+	x, y          int
+	width, height int
+}
+
+func (z zone) contains(x int, y int) bool {
+	return x >= z.x && x < z.x+z.width && y >= z.y && y < z.y+z.height
+}
+
+type zones struct {
+	startPause zone
+	stop       zone
+	progress   zone
+}
+
+// zones works out where the buttons and the progress bar land on screen. It
+// mirrors the centring done by JoinVertical, the border style and Place in
+// View, so it stays correct as the window is resized.
+func (m TimerView) zones() zones {
+	if m.layout == InlineLayout {
+		// The view isn't at a known place on the screen, so nothing can be clicked.
+		return zones{}
+	}
+	if m.layout == MiniLayout {
+		// The progress bar shares the single line with the time left.
+		line := m.miniView()
+		return zones{
+			progress: zone{
+				x:      placeOffset(m.style.width, lipgloss.Width(line)),
+				y:      placeOffset(m.style.height, 1),
+				width:  lipgloss.Width(line),
+				height: 1,
+			},
+		}
+	}
+
+	l := m.arrange()
+	frame := m.frameStyle()
+	ui := lipgloss.JoinVertical(lipgloss.Center, l.parts...)
+	box := frame.Render(ui)
+
+	x := placeOffset(m.style.width, lipgloss.Width(box)) + frame.GetBorderLeftSize() + frame.GetPaddingLeft()
+	y := placeOffset(m.style.height, lipgloss.Height(box)) + frame.GetBorderTopWidth() + frame.GetPaddingTop()
+	uiWidth := lipgloss.Width(ui)
+
+	rowOf := func(part int) int {
+		row := y
+		for _, p := range l.parts[:part] {
+			row += lipgloss.Height(p)
+		}
+		return row
+	}
+
+	buttonsX := x + joinOffset(uiWidth, lipgloss.Width(l.parts[l.buttons]))
+	buttonsY := rowOf(l.buttons)
+	startPause := m.getStartPauseButton()
+	stop := m.getStopButton()
+	buttonStyle, _ := m.buttonStyles()
+
+	return zones{
+		startPause: buttonZone(buttonStyle, buttonsX, buttonsY, startPause),
+		stop:       buttonZone(buttonStyle, buttonsX+lipgloss.Width(startPause), buttonsY, stop),
+		progress: zone{
+			x:      x + joinOffset(uiWidth, lipgloss.Width(l.parts[l.progress])),
+			y:      rowOf(l.progress),
+			width:  lipgloss.Width(l.parts[l.progress]),
+			height: lipgloss.Height(l.parts[l.progress]),
+		},
+	}
+}
+
+// buttonZone is the area of a rendered button at x, y without its margins.
+func buttonZone(style lipgloss.Style, x int, y int, button string) zone {
+	return zone{
+		x:      x + style.GetMarginLeft(),
+		y:      y + style.GetMarginTop(),
+		width:  lipgloss.Width(button) - style.GetHorizontalMargins(),
+		height: lipgloss.Height(button) - style.GetVerticalMargins(),
+	}
+}
+
+// placeOffset is where lipgloss.Place puts the start of a centred block.
+func placeOffset(space int, size int) int {
+	gap := space - size
+	if gap <= 0 {
+		return 0
+	}
+	return gap - int(math.Round(float64(gap)*0.5))
+}
+
+// joinOffset is where lipgloss.JoinVertical puts the start of a centred line.
+func joinOffset(space int, size int) int {
+	gap := space - size
+	if gap < 1 {
+		return 0
+	}
+	return int(math.Round(float64(gap) * 0.5))
+}
+
+func handleMouseMessage(m TimerView, msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	z := m.zones()
+	switch msg.Type {
+	case tea.MouseLeft:
+		if z.startPause.contains(msg.X, msg.Y) {
+			m.activeButton = startPauseButton
+			return startPauseTimer(m)
+		} else if z.stop.contains(msg.X, msg.Y) {
+			m.activeButton = stopButton
+			return m.style.onStop(m)
+		}
+	case tea.MouseWheelUp:
+		if z.progress.contains(msg.X, msg.Y) {
+			return nudge(m, nudgeStep), nil
+		}
+	case tea.MouseWheelDown:
+		if z.progress.contains(msg.X, msg.Y) {
+			return nudge(m, -nudgeStep), nil
+		}
+	}
+	return m, nil
+}
+
+// nudge adds d to the time left, keeping at least a second on the clock.
+func nudge(m TimerView, d time.Duration) TimerView {
+	if m.timer.Timeout+d < time.Second {
+		d = time.Second - m.timer.Timeout
+	}
+	m.timer.Timeout += d
+	m.nudged += d
+	m.percentComplete = m.timeUsed()
+	m.progressBar.SetPercent(m.percentComplete)
+	return m
+}
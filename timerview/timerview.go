@@ -11,6 +11,10 @@ import (
 	"github.com/charmbracelet/bubbles/timer"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/guysherman/tomato/bigdigits"
+	"github.com/guysherman/tomato/notifications"
+	"github.com/guysherman/tomato/terminal"
+	"github.com/guysherman/tomato/theme"
 )
 
 type activeButton int64
@@ -23,12 +27,27 @@ const (
 type StopBehavior func(TimerView) (tea.Model, tea.Cmd)
 type StartBehavior func()
 type TimeoutBehavior func()
+type WarningBehavior func(Warning)
+type ForecastBehavior func(end time.Time) string
+
+// Warning is an advance notice sent when Before is left on the timer.
+type Warning struct {
+	Before time.Duration
+	Title  string
+	Body   string
+	Hook   string
+}
 
 type TimerViewStyle struct {
 	activeButtonStyle   lipgloss.Style
 	inactiveButtonStyle lipgloss.Style
 	borderStyle         lipgloss.Style
-	progressBarColor    string
+	progressBarStart    string
+	progressBarEnd      string
+	progressBarEmpty    string
+	textStyle           lipgloss.Style
+	keys                KeyMap
+	layout              Layout
 	startText           string
 	pauseText           string
 	resumeText          string
@@ -39,6 +58,37 @@ type TimerViewStyle struct {
 	onStop              StopBehavior
 	onStart             StartBehavior
 	onTimeout           TimeoutBehavior
+	onWarning           WarningBehavior
+	warnings            []Warning
+	phaseName           string
+	reporter            terminal.Reporter
+	font                string
+	cycle               Cycle
+	task                string
+	project             string
+	goals               string
+	forecast            ForecastBehavior
+}
+
+// ModeOptions carries the per-period settings for NewFocusMode and NewBreakMode.
+type ModeOptions struct {
+	NoiseModeScript   string
+	QuietModeScript   string
+	NotificationTitle string
+	NotificationBody  string
+	Notifier          notifications.Notifier
+	Warnings          []Warning
+	Phase             string
+	Reporter          terminal.Reporter
+	Font              string
+	Cycle             Cycle
+	Task              string
+	Project           string
+	Goals             string
+	Forecast          ForecastBehavior
+	Colors            theme.Phase
+	Keys              KeyMap
+	Layout            Layout
 }
 
 type TimerView struct {
@@ -46,63 +96,66 @@ type TimerView struct {
 	started          bool
 	originalDuration time.Duration
 	originalInterval time.Duration
+	nudged           time.Duration
+	startedAt        time.Time
+	interruptions    int
+	layout           Layout
 	progressBar      progress.Model
 	percentComplete  float64
-	keymaps          []key.Binding
+	keys             KeyMap
 	help             help.Model
 	activeButton     activeButton
 	style            TimerViewStyle
 }
 
-func NewTimerView(duration string, interval time.Duration, style TimerViewStyle) TimerView {
-	focusDuration, err := time.ParseDuration(duration)
-	if err != nil {
-		focusDuration = time.Minute * 25
+func NewTimerView(duration time.Duration, interval time.Duration, style TimerViewStyle) TimerView {
+	layout := chooseLayout(style.layout, style.width, style.height)
+	fill := progress.WithGradient(style.progressBarStart, style.progressBarEnd)
+	if style.progressBarStart == style.progressBarEnd {
+		fill = progress.WithSolidFill(style.progressBarStart)
+	}
+	progressBar := progress.New(
+		fill,
+		progress.WithoutPercentage(),
+		progress.WithWidth(progressWidth(layout, style.width)),
+		progress.WithColorProfile(lipgloss.ColorProfile()),
+	)
+	keys := style.keys
+	if keys.isZero() {
+		keys = DefaultKeyMap()
+	}
+	keys.Stop.SetHelp(keys.Stop.Help().Key, style.stopHelpText)
+	keys.Pause.SetEnabled(false)
+	keys.Stop.SetEnabled(false)
+
+	if style.progressBarEmpty != "" {
+		progressBar.EmptyColor = style.progressBarEmpty
 	}
 
+	helpModel := help.NewModel()
+	helpModel.Width = style.width
+
 	return TimerView{
-		timer: timer.NewWithInterval(focusDuration, interval),
-		progressBar: progress.New(
-			progress.WithSolidFill(style.progressBarColor),
-			progress.WithoutPercentage(),
-			progress.WithWidth(int(float64(style.width)*0.64)),
-		),
-		originalDuration: focusDuration,
+		timer:            timer.NewWithInterval(duration, interval),
+		progressBar:      progressBar,
+		originalDuration: duration,
 		originalInterval: interval,
 		started:          false,
 		percentComplete:  0,
-		keymaps: []key.Binding{
-			key.NewBinding(
-				key.WithKeys(tea.KeySpace.String()),
-				key.WithHelp("space", "Starts the timer"),
-			),
-			key.NewBinding(
-				key.WithKeys(tea.KeySpace.String()),
-				key.WithHelp("space", "Pauses the timer"),
-				key.WithDisabled(),
-			),
-			key.NewBinding(
-				key.WithKeys("s"),
-				key.WithHelp("s", style.stopHelpText),
-				key.WithDisabled(),
-			),
-			key.NewBinding(
-				key.WithKeys("q"),
-				key.WithHelp("q", "Quits the application"),
-			),
-		},
-		help:         help.NewModel(),
-		activeButton: startPauseButton,
-		style:        style,
+		keys:             keys,
+		help:             helpModel,
+		layout:           layout,
+		activeButton:     startPauseButton,
+		style:            style,
 	}
 }
 
 func (m TimerView) getStartPauseButton() string {
 	var buttonStyle lipgloss.Style
 	if m.activeButton == startPauseButton {
-		buttonStyle = m.style.activeButtonStyle
+		buttonStyle, _ = m.buttonStyles()
 	} else {
-		buttonStyle = m.style.inactiveButtonStyle
+		_, buttonStyle = m.buttonStyles()
 	}
 	startPauseButtonText := m.getStartPauseButtonText()
 	startPauseButton := buttonStyle.Render(startPauseButtonText)
@@ -112,27 +165,151 @@ func (m TimerView) getStartPauseButton() string {
 func (m TimerView) getStopButton() string {
 	var buttonStyle lipgloss.Style
 	if m.activeButton == stopButton {
-		buttonStyle = m.style.activeButtonStyle
+		buttonStyle, _ = m.buttonStyles()
 	} else {
-		buttonStyle = m.style.inactiveButtonStyle
+		_, buttonStyle = m.buttonStyles()
 	}
 	cancelButton := buttonStyle.Render(m.style.stopText)
 	return cancelButton
 }
 
-func (m TimerView) View() string {
+// arrangement is the ui of the timer view, as the blocks View stacks
+// vertically, along with where the progress bar and the buttons are in that
+// stack.
+type arrangement struct {
+	parts    []string
+	progress int
+	buttons  int
+}
+
+func (m TimerView) arrange() arrangement {
 	startPauseButton := m.getStartPauseButton()
 	cancelButton := m.getStopButton()
 	buttons := lipgloss.JoinHorizontal(lipgloss.Top, startPauseButton, cancelButton)
-
 	pbar := m.progressBar.ViewAs(m.progressBar.Percent())
-	timeLeft := fmt.Sprintf("\n%s\n", m.timer.View())
-	help := fmt.Sprintf("\n\n%s", m.help.ShortHelpView(m.keymaps))
-	ui := lipgloss.JoinVertical(lipgloss.Center, pbar, timeLeft, buttons, help)
-	block := lipgloss.Place(m.style.width, m.style.height, lipgloss.Center, lipgloss.Center, m.style.borderStyle.Render(ui))
+	parts := []string{pbar}
+
+	if m.layout == CompactLayout {
+		// The text lines are cut short rather than wrapped, so the frame keeps
+		// to the window.
+		width := m.style.width - m.frameStyle().GetHorizontalFrameSize()
+		lines := m.details()
+		if cycle := m.style.cycle.View(); cycle != "" {
+			lines = append([]string{cycle}, lines...)
+		}
+		for _, line := range append(lines, m.scheduleView()) {
+			parts = append(parts, Truncate(line, width))
+		}
+		timeLeft := m.style.textStyle.Render(m.timeLeftView(0, append(parts, buttons)...))
+		parts = append(parts, timeLeft, buttons)
+		return arrangement{parts: parts, progress: 0, buttons: len(parts) - 1}
+	}
+
+	help := fmt.Sprintf("\n\n%s", m.helpView())
+	if cycle := m.style.cycle.View(); cycle != "" {
+		parts = append(parts, "\n"+cycle)
+	}
+	parts = append(parts, m.details()...)
+	parts = append(parts, m.scheduleView())
+	timeLeft := fmt.Sprintf("\n%s\n", m.style.textStyle.Render(m.timeLeftView(2, append(parts, buttons, help)...)))
+	parts = append(parts, timeLeft, buttons, help)
+	return arrangement{parts: parts, progress: 0, buttons: len(parts) - 2}
+}
+
+// details are the lines about what the period is for and the progress
+// towards the goals, leaving out any that are empty.
+func (m TimerView) details() []string {
+	lines := []string{}
+	for _, line := range []string{m.style.task, m.style.project, m.style.goals} {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func (m TimerView) View() string {
+	if m.layout == InlineLayout {
+		return m.inlineView()
+	}
+	if m.layout == MiniLayout {
+		return lipgloss.Place(m.style.width, m.style.height, lipgloss.Center, lipgloss.Center, m.miniView())
+	}
+
+	ui := lipgloss.JoinVertical(lipgloss.Center, m.arrange().parts...)
+	block := lipgloss.Place(m.style.width, m.style.height, lipgloss.Center, lipgloss.Center, m.frameStyle().Render(ui))
 	return block
 }
 
+// helpView renders the short help line, or when toggled on, every binding
+// in columns grouped by category.
+func (m TimerView) helpView() string {
+	if !m.help.ShowAll {
+		return m.help.View(m.keys)
+	}
+
+	// The columns wrap onto more rows when they don't fit across the window.
+	width := m.style.width - m.frameStyle().GetHorizontalFrameSize()
+	rows := []string{}
+	columns := []string{}
+	addRow := func() {
+		if len(rows) > 0 {
+			rows = append(rows, "")
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, columns...))
+		columns = nil
+	}
+	for _, g := range m.keys.HelpGroups() {
+		title := lipgloss.NewStyle().Bold(true).Render(g.Title)
+		bindings := m.help.FullHelpView([][]key.Binding{g.Bindings})
+		column := lipgloss.NewStyle().MarginRight(4).Render(lipgloss.JoinVertical(lipgloss.Left, title, bindings))
+		if len(columns) > 0 && width > 0 && lipgloss.Width(lipgloss.JoinHorizontal(lipgloss.Top, append(columns, column)...)) > width {
+			addRow()
+		}
+		columns = append(columns, column)
+	}
+	addRow()
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// now is swapped out by tests.
+var now = time.Now
+
+// scheduleView shows the clock time the period will end at, were it to run
+// uninterrupted from now, along with the forecast for the rest of the day.
+func (m TimerView) scheduleView() string {
+	end := now().Add(m.timer.Timeout)
+	schedule := fmt.Sprintf("ends at %s", end.Format("15:04"))
+	if m.style.forecast != nil {
+		if forecast := m.style.forecast(end); forecast != "" {
+			schedule = fmt.Sprintf("%s   %s", schedule, forecast)
+		}
+	}
+	return schedule
+}
+
+// timeLeftView renders the time left in big digits when the font is known and
+// there is room for them, and the spacing around them, alongside the rest of
+// the ui, or as small text otherwise.
+func (m TimerView) timeLeftView(spacing int, others ...string) string {
+	font, ok := bigdigits.Lookup(m.style.font)
+	if !ok {
+		return m.timer.View()
+	}
+
+	width := m.style.width - m.frameStyle().GetHorizontalFrameSize()
+	height := m.style.height - m.frameStyle().GetVerticalFrameSize() - spacing
+	for _, o := range others {
+		height -= lipgloss.Height(o)
+	}
+
+	digits, ok := font.Fit(terminal.FormatClock(m.timer.Timeout), width, height)
+	if !ok {
+		return m.timer.View()
+	}
+	return digits
+}
+
 func (m TimerView) getStartPauseButtonText() string {
 	if !m.started {
 		return m.style.startText
@@ -155,6 +332,10 @@ func (m TimerView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return handleResizeMessage(m, msg)
 	case timer.TimeoutMsg:
 		return handleTimeoutMessage(m, msg)
+	case tea.MouseMsg:
+		return handleMouseMessage(m, msg)
+	case StartMsg:
+		return handleStartMessage(m)
 	}
 	return m, nil
 }
@@ -167,18 +348,21 @@ func (m TimerView) PercentComplete() float64 {
 	return m.percentComplete
 }
 func handleKeyMessage(m TimerView, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch keypress := msg.String(); keypress {
-	case tea.KeySpace.String():
-		return handleSpacebar(m)
-	case tea.KeyEnter.String():
-		return handleEnterPressed(m)
-	case "s":
-		return handleSPressed(m)
-	case "h", tea.KeyLeft.String():
-		return handleHPressed(m)
-	case "l", tea.KeyRight.String():
-		return handleLPressed(m)
-	case "q":
+	switch {
+	case pressed(msg, m.keys.Start), pressed(msg, m.keys.Pause):
+		return handleStartPausePressed(m)
+	case pressed(msg, m.keys.Select):
+		return handleSelectPressed(m)
+	case pressed(msg, m.keys.Stop):
+		return handleStopPressed(m)
+	case pressed(msg, m.keys.Left):
+		return handleLeftPressed(m)
+	case pressed(msg, m.keys.Right):
+		return handleRightPressed(m)
+	case pressed(msg, m.keys.Help):
+		m.help.ShowAll = !m.help.ShowAll
+		return m, nil
+	case pressed(msg, m.keys.Quit):
 		return m, tea.Quit
 	}
 
@@ -187,18 +371,80 @@ func handleKeyMessage(m TimerView, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 func handleTickMessage(m TimerView, msg timer.TickMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
-	timeUsed := m.originalDuration.Hours() - m.timer.Timeout.Hours()
-	m.percentComplete = timeUsed / m.originalDuration.Hours()
+	m.percentComplete = m.timeUsed()
 	m.progressBar.SetPercent(m.percentComplete)
+	remaining := m.timer.Timeout
 	m.timer, cmd = m.timer.Update(msg)
-	return m, cmd
+	return m, tea.Batch(cmd, checkWarnings(m, remaining), reportProgress(m))
+}
+
+// reportProgress returns a command that shows the time left in the terminal
+// title and taskbar, so the escape sequences aren't written from Update.
+func reportProgress(m TimerView) tea.Cmd {
+	reporter := m.style.reporter
+	if !reporter.Title && !reporter.Progress {
+		return nil
+	}
+	state := terminal.ProgressNormal
+	if !m.timer.Running() {
+		state = terminal.ProgressPaused
+	}
+	title := fmt.Sprintf("🍅 %s %s", terminal.FormatClock(m.timer.Timeout), m.style.phaseName)
+	percent := m.percentComplete
+	return func() tea.Msg {
+		reporter.Report(title, state, percent)
+		return nil
+	}
+}
+
+// checkWarnings returns a command firing any warnings whose threshold was
+// crossed by the last tick, given how much time was remaining before it.
+func checkWarnings(m TimerView, previousRemaining time.Duration) tea.Cmd {
+	if m.style.onWarning == nil {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, w := range m.style.warnings {
+		if previousRemaining > w.Before && m.timer.Timeout <= w.Before && !m.timer.Timedout() {
+			w := w
+			onWarning := m.style.onWarning
+			cmds = append(cmds, perform(func() { onWarning(w) }))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// perform returns a command that calls f, so that scripts, hooks and
+// notifications run outside Update rather than holding up the UI.
+func perform(f func()) tea.Cmd {
+	if f == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		f()
+		return nil
+	}
+}
+
+// timeUsed is the fraction of the period that has passed, allowing for any
+// time nudged on or off with the mouse wheel.
+func (m TimerView) timeUsed() float64 {
+	duration := m.originalDuration + m.nudged
+	return (duration.Hours() - m.timer.Timeout.Hours()) / duration.Hours()
+}
+
+func handleStartPausePressed(m TimerView) (tea.Model, tea.Cmd) {
+	return startPauseTimer(m)
 }
 
-func handleSpacebar(m TimerView) (tea.Model, tea.Cmd) {
+func handleStartMessage(m TimerView) (tea.Model, tea.Cmd) {
+	if m.started {
+		return m, nil
+	}
 	return startPauseTimer(m)
 }
 
-func handleEnterPressed(m TimerView) (tea.Model, tea.Cmd) {
+func handleSelectPressed(m TimerView) (tea.Model, tea.Cmd) {
 	if m.activeButton == startPauseButton {
 		return startPauseTimer(m)
 	} else {
@@ -208,34 +454,39 @@ func handleEnterPressed(m TimerView) (tea.Model, tea.Cmd) {
 
 func startPauseTimer(m TimerView) (tea.Model, tea.Cmd) {
 	if !m.started {
-		if m.style.onStart != nil {
-			m.style.onStart()
-		}
+		onStart := perform(m.style.onStart)
 		m.started = true
-		m.keymaps[0].SetEnabled(false)
-		m.keymaps[1].SetEnabled(true)
-		m.keymaps[2].SetEnabled(true)
-		return m, m.timer.Init()
+		m.startedAt = now()
+		m.keys.Start.SetEnabled(false)
+		m.keys.Pause.SetEnabled(true)
+		m.keys.Stop.SetEnabled(true)
+		return m, tea.Sequentially(onStart, m.timer.Init())
 	} else {
+		if m.timer.Running() {
+			m.interruptions++
+		}
 		return m, m.timer.Toggle()
 	}
 }
 
 func stopTimer(m TimerView) (tea.Model, tea.Cmd) {
-	newModel := NewTimerView(m.originalDuration.String(), m.originalInterval, m.style)
+	newModel := NewTimerView(m.originalDuration, m.originalInterval, m.style)
+	if m.started {
+		return newModel, timerVoided(m)
+	}
 	return newModel, nil
 }
 
-func handleSPressed(m TimerView) (tea.Model, tea.Cmd) {
+func handleStopPressed(m TimerView) (tea.Model, tea.Cmd) {
 	return m.style.onStop(m)
 }
 
-func handleHPressed(m TimerView) (tea.Model, tea.Cmd) {
+func handleLeftPressed(m TimerView) (tea.Model, tea.Cmd) {
 	m.activeButton = startPauseButton
 	return m, nil
 }
 
-func handleLPressed(m TimerView) (tea.Model, tea.Cmd) {
+func handleRightPressed(m TimerView) (tea.Model, tea.Cmd) {
 	m.activeButton = stopButton
 	return m, nil
 }
@@ -243,32 +494,81 @@ func handleLPressed(m TimerView) (tea.Model, tea.Cmd) {
 func handleStartStopMessage(m TimerView, msg timer.StartStopMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	m.timer, cmd = m.timer.Update(msg)
-	m.keymaps[0].SetEnabled(!m.timer.Running())
-	m.keymaps[1].SetEnabled(m.timer.Running())
-	m.keymaps[2].SetEnabled(true)
-	return m, cmd
+	m.keys.Start.SetEnabled(!m.timer.Running())
+	m.keys.Pause.SetEnabled(m.timer.Running())
+	m.keys.Stop.SetEnabled(true)
+	return m, tea.Batch(cmd, reportProgress(m))
 }
 
 func handleResizeMessage(m TimerView, msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
 	m.style.width = msg.Width
 	m.style.height = msg.Height
-	m.progressBar.Width = int(float64(msg.Width) * 0.64)
+	m.layout = chooseLayout(m.style.layout, msg.Width, msg.Height)
+	m.progressBar.Width = progressWidth(m.layout, msg.Width)
+	m.help.Width = msg.Width
 
 	return m, nil
 }
 
 func handleTimeoutMessage(m TimerView, msg timer.TimeoutMsg) (tea.Model, tea.Cmd) {
-	if m.style.onTimeout != nil {
-		m.style.onTimeout()
+	// A timeout from the timer of a period that was stopped mustn't end this one.
+	if msg.ID != m.timer.ID() {
+		return m, nil
 	}
-	return m, focusComplete
+	return m, tea.Sequentially(perform(m.style.onTimeout), focusComplete(m, false))
 }
 
-func focusComplete() tea.Msg {
-	return TimerCompleteMsg{}
+// period sums up the period so far.
+func (m TimerView) period() Period {
+	return Period{
+		Started:       m.startedAt,
+		Planned:       m.originalDuration,
+		Elapsed:       m.originalDuration + m.nudged - m.timer.Timeout,
+		Interruptions: m.interruptions,
+	}
+}
+
+// Started reports whether the timer has been started, so the period is under
+// way.
+func (m TimerView) Started() bool {
+	return m.started
+}
+
+func focusComplete(m TimerView, skipped bool) tea.Cmd {
+	period := m.period()
+	if !m.started {
+		period.Elapsed = 0
+	}
+	return func() tea.Msg {
+		return TimerCompleteMsg{Period: period, Skipped: skipped}
+	}
+}
+
+func timerVoided(m TimerView) tea.Cmd {
+	period := m.period()
+	return func() tea.Msg {
+		return TimerVoidedMsg{Period: period}
+	}
+}
+
+func phaseName(name string, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+func sendWarning(notifier notifications.Notifier, w Warning) {
+	notifier.Notify(w.Title, w.Body)
+	if w.Hook != "" {
+		RunScript(w.Hook)
+	}
 }
 
-func runScript(scriptPath string) {
+// RunScript runs the script at scriptPath, such as the quiet and noise mode
+// scripts or a warning's hook, and waits for it to finish. A script that's
+// missing or fails is ignored.
+func RunScript(scriptPath string) {
 	cmd := exec.Command(scriptPath)
 	cmd.Output()
 }
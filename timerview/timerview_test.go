@@ -2,18 +2,25 @@ package timerview
 
 import (
 	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/charmbracelet/bubbles/timer"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guysherman/tomato/bigdigits"
+	"github.com/guysherman/tomato/notifications"
+	"github.com/guysherman/tomato/terminal"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
 func TestTimerView(t *testing.T) {
 	Convey("TimerView", t, func() {
 		Convey("timer is not running", func() {
-			fm := NewFocusMode("1s", time.Millisecond, 120, 40)
+			fm := NewFocusMode(time.Second, time.Millisecond, 120, 40, ModeOptions{})
 			Convey("Pressing spacebar starts the timer", func() {
 				msg := tea.KeyMsg{
 					Type: tea.KeySpace,
@@ -26,9 +33,9 @@ func TestTimerView(t *testing.T) {
 
 				fm, cmd = fm.Update(msg2)
 				So(fm.(TimerView).timer.Running(), ShouldBeTrue)
-				So(fm.(TimerView).keymaps[0].Enabled(), ShouldBeFalse)
-				So(fm.(TimerView).keymaps[1].Enabled(), ShouldBeTrue)
-				So(fm.(TimerView).keymaps[2].Enabled(), ShouldBeTrue)
+				So(fm.(TimerView).keys.Start.Enabled(), ShouldBeFalse)
+				So(fm.(TimerView).keys.Pause.Enabled(), ShouldBeTrue)
+				So(fm.(TimerView).keys.Stop.Enabled(), ShouldBeTrue)
 			})
 
 			Convey("Pressing q exits the application", func() {
@@ -43,14 +50,23 @@ func TestTimerView(t *testing.T) {
 				So(fmt.Sprintf("%T", msg2), ShouldEqual, fmt.Sprintf("%T", tea.Quit()))
 			})
 
+			Convey("StartMsg starts the timer", func() {
+				fm, cmd := fm.Update(StartMsg{})
+				So(fm.(TimerView).started, ShouldBeTrue)
+				So(fmt.Sprintf("%T", cmd()), ShouldEqual, fmt.Sprintf("%T", timer.TickMsg{}))
+
+				_, cmd = fm.Update(StartMsg{})
+				So(cmd, ShouldBeNil)
+			})
+
 			Reset(func() {
-				fm = NewTimerView("1s", time.Millisecond, TimerViewStyle{})
+				fm = NewTimerView(time.Second, time.Millisecond, TimerViewStyle{})
 			})
 		})
 
 		Convey("Buttons", func() {
 			var fm tea.Model
-			fm = NewTimerView("1s", time.Millisecond, TimerViewStyle{})
+			fm = NewTimerView(time.Second, time.Millisecond, TimerViewStyle{})
 			Convey("l switches active button to stop", func() {
 				msg := tea.KeyMsg{
 					Type:  tea.KeyRunes,
@@ -150,18 +166,255 @@ func TestTimerView(t *testing.T) {
 				}
 
 				fm, cmd := fm.Update(msg)
-				So(cmd, ShouldBeNil)
+				So(cmd(), ShouldHaveSameTypeAs, TimerVoidedMsg{})
 				So(fm.(TimerView).started, ShouldBeFalse)
 				So(fm.(TimerView).activeButton, ShouldEqual, startPauseButton)
 			})
 
 			Reset(func() {
-				fm = NewFocusMode("1s", time.Millisecond, 120, 40)
+				fm = NewFocusMode(time.Second, time.Millisecond, 120, 40, ModeOptions{})
+			})
+		})
+
+		Convey("Time left", func() {
+			Convey("is drawn in big digits when there is room", func() {
+				fm := NewFocusMode(25*time.Minute, time.Second, 60, 20, ModeOptions{Font: "ascii", Layout: FullLayout})
+				font, _ := bigdigits.Lookup("ascii")
+				So(fm.View(), ShouldContainSubstring, strings.Split(font.Render("25:00", 1), "\n")[0])
+				So(fm.View(), ShouldNotContainSubstring, "25m0s")
+			})
+
+			Convey("falls back to small text in a small window", func() {
+				fm := NewFocusMode(25*time.Minute, time.Second, 40, 12, ModeOptions{Font: "ascii", Layout: FullLayout})
+				So(fm.View(), ShouldContainSubstring, "25m0s")
+			})
+		})
+
+		Convey("Cycle", func() {
+			Convey("shows completed, voided and remaining tomatoes", func() {
+				c := Cycle{Completed: 2, Voided: 1, Length: 4, Today: 6, NextPhase: "Short Break"}
+				So(c.View(), ShouldEqual, "●●✕○   6 today   next: Short Break")
+			})
+
+			Convey("never draws more than the cycle's length of empty tomatoes", func() {
+				c := Cycle{Completed: 3, Voided: 2, Length: 4, NextPhase: "Long Break"}
+				So(c.View(), ShouldStartWith, "●●●✕✕   0 today")
+			})
+
+			Convey("is drawn in the timer view", func() {
+				fm := NewFocusMode(25*time.Minute, time.Second, 120, 40, ModeOptions{Cycle: Cycle{Length: 4, NextPhase: "Short Break"}})
+				So(fm.View(), ShouldContainSubstring, "○○○○   0 today   next: Short Break")
+			})
+		})
+
+		Convey("Schedule", func() {
+			now = func() time.Time { return time.Date(2026, 10, 19, 9, 50, 0, 0, time.Local) }
+			fm := NewFocusMode(25*time.Minute, time.Second, 120, 40, ModeOptions{
+				Forecast: func(end time.Time) string {
+					return fmt.Sprintf("forecast from %s", end.Format("15:04"))
+				},
+			})
+
+			Convey("shows when the period ends, and the forecast", func() {
+				So(fm.View(), ShouldContainSubstring, "ends at 10:15   forecast from 10:15")
+			})
+
+			Convey("moves the end while paused", func() {
+				now = func() time.Time { return time.Date(2026, 10, 19, 9, 55, 0, 0, time.Local) }
+				So(fm.View(), ShouldContainSubstring, "ends at 10:20")
+			})
+
+			Reset(func() {
+				now = time.Now
+			})
+		})
+
+		Convey("Key bindings", func() {
+			Convey("can be remapped", func() {
+				keys, err := NewKeyMap(KeyBindings{"stop": {"x"}, "quit": {"q", "ctrl+c"}})
+				So(err, ShouldBeNil)
+				fm := NewFocusMode(time.Second, time.Millisecond, 120, 40, ModeOptions{Keys: keys})
+				fm.started = true
+
+				next, cmd := fm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+				So(cmd(), ShouldHaveSameTypeAs, TimerVoidedMsg{})
+				So(next.(TimerView).started, ShouldBeFalse)
+
+				_, cmd = fm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+				So(cmd, ShouldBeNil)
+
+				_, cmd = fm.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+				So(fmt.Sprintf("%T", cmd()), ShouldEqual, fmt.Sprintf("%T", tea.Quit()))
+			})
+
+			Convey("drive the help text", func() {
+				keys, _ := NewKeyMap(KeyBindings{"quit": {"q", "ctrl+c"}})
+				fm := NewFocusMode(time.Second, time.Millisecond, 120, 40, ModeOptions{Keys: keys})
+				So(fm.View(), ShouldContainSubstring, "q/ctrl+c Quits the application")
+			})
+
+			Convey("? toggles the full help", func() {
+				fm := NewFocusMode(time.Second, time.Millisecond, 120, 40, ModeOptions{})
+				So(fm.View(), ShouldNotContainSubstring, "Navigation")
+
+				next, _ := fm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+				So(next.View(), ShouldContainSubstring, "Navigation")
+				So(next.View(), ShouldContainSubstring, "Selects the button to the left")
+
+				next, _ = next.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+				So(next.View(), ShouldNotContainSubstring, "Navigation")
+			})
+
+			Convey("report conflicts", func() {
+				_, err := NewKeyMap(KeyBindings{"stop": {"q"}})
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, `"q" is bound to quit and stop`)
+			})
+
+			Convey("reject unknown actions", func() {
+				_, err := NewKeyMap(KeyBindings{"explode": {"x"}})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("cover the other screens", func() {
+				keys, err := NewKeyMap(KeyBindings{"tasks.add": {"n"}, "plan.add": {"s"}})
+				So(err, ShouldBeNil)
+				So(keys.Binding("tasks.add").Keys(), ShouldResemble, []string{"n"})
+				So(keys.Binding("tasks.done").Keys(), ShouldResemble, []string{"x"})
+
+				_, err = NewKeyMap(KeyBindings{"tasks.add": {"x"}})
+				So(err.Error(), ShouldContainSubstring, `"x" is bound to tasks.add and tasks.done`)
+				_, err = NewKeyMap(KeyBindings{"stats.up": {"tab"}})
+				So(err.Error(), ShouldContainSubstring, `"tab" is bound to next_tab and stats.up`)
+
+				titles := []string{}
+				for _, g := range keys.HelpGroups() {
+					titles = append(titles, g.Title)
+				}
+				So(titles, ShouldResemble, []string{"Timer", "Navigation", "App", "Plan", "Tasks", "Stats", "Settings", "Reflection", "Label"})
+			})
+		})
+
+		Convey("Layout", func() {
+			Convey("is chosen from the window size", func() {
+				So(chooseLayout(AutoLayout, 120, 40), ShouldEqual, FullLayout)
+				So(chooseLayout(AutoLayout, 50, 12), ShouldEqual, CompactLayout)
+				So(chooseLayout(AutoLayout, 30, 6), ShouldEqual, MiniLayout)
+				So(chooseLayout(MiniLayout, 120, 40), ShouldEqual, MiniLayout)
+			})
+
+			Convey("follows resizes", func() {
+				var fm tea.Model = NewFocusMode(25*time.Minute, time.Second, 120, 40, ModeOptions{})
+				fm, _ = fm.Update(tea.WindowSizeMsg{Width: 30, Height: 6})
+				So(fm.(TimerView).layout, ShouldEqual, MiniLayout)
+			})
+
+			Convey("compact drops the help", func() {
+				fm := NewFocusMode(25*time.Minute, time.Second, 50, 12, ModeOptions{Font: "small"})
+				So(fm.View(), ShouldContainSubstring, "Stop")
+				So(fm.View(), ShouldNotContainSubstring, "Quits the application")
+			})
+
+			Convey("compact cuts long lines to fit the window", func() {
+				fm := NewFocusMode(25*time.Minute, time.Second, 36, 12, ModeOptions{
+					Font:  "small",
+					Cycle: Cycle{Completed: 2, Voided: 1, Length: 4, Today: 12, NextPhase: "Long Break"},
+					Task:  "Write the quarterly report for the board",
+				})
+				So(fm.layout, ShouldEqual, CompactLayout)
+				for _, line := range strings.Split(fm.View(), "\n") {
+					So(lipgloss.Width(line), ShouldBeLessThanOrEqualTo, 36)
+				}
+				So(fm.View(), ShouldContainSubstring, "…")
+			})
+
+			Convey("mini is a single line", func() {
+				fm := NewFocusMode(25*time.Minute, time.Second, 30, 6, ModeOptions{Phase: "Focus"})
+				lines := strings.Split(strings.TrimSpace(fm.View()), "\n")
+				So(len(lines), ShouldEqual, 1)
+				So(lines[0], ShouldStartWith, "Focus 25:00 ")
+			})
+
+			Convey("inline is the mini line with the help under it", func() {
+				fm := NewFocusMode(25*time.Minute, time.Second, 80, 40, ModeOptions{Phase: "Focus", Layout: InlineLayout})
+				lines := strings.Split(fm.View(), "\n")
+				So(len(lines), ShouldEqual, 2)
+				So(lines[0], ShouldStartWith, "Focus 25:00 ")
+				So(lines[1], ShouldContainSubstring, "Starts the timer")
+			})
+
+			Convey("can be parsed from a flag", func() {
+				l, err := ParseLayout("compact")
+				So(err, ShouldBeNil)
+				So(l, ShouldEqual, CompactLayout)
+				_, err = ParseLayout("huge")
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("Mouse", func() {
+			// find returns the screen position of text in the rendered view.
+			find := func(m tea.Model, text string) (int, int) {
+				plain := regexp.MustCompile("\x1b\\[[0-9;]*m").ReplaceAllString(m.View(), "")
+				for y, line := range strings.Split(plain, "\n") {
+					if x := strings.Index(line, text); x >= 0 {
+						return len([]rune(line[:x])), y
+					}
+				}
+				return -1, -1
+			}
+
+			var fm tea.Model = NewFocusMode(25*time.Minute, time.Second, 120, 40, ModeOptions{Font: "small"})
+
+			Convey("clicking Start starts the timer", func() {
+				x, y := find(fm, "Start")
+				_, cmd := fm.Update(tea.MouseMsg{X: x + 2, Y: y, Type: tea.MouseLeft})
+				So(cmd, ShouldNotBeNil)
+				So(fmt.Sprintf("%T", cmd()), ShouldEqual, fmt.Sprintf("%T", timer.TickMsg{}))
+			})
+
+			Convey("clicking Stop after a resize stops the timer", func() {
+				fm, _ = fm.Update(tea.WindowSizeMsg{Width: 97, Height: 31})
+				fmm := fm.(TimerView)
+				fmm.started = true
+				x, y := find(fmm, "Stop")
+
+				next, cmd := fmm.Update(tea.MouseMsg{X: x, Y: y, Type: tea.MouseLeft})
+				So(cmd(), ShouldHaveSameTypeAs, TimerVoidedMsg{})
+				So(next.(TimerView).started, ShouldBeFalse)
+			})
+
+			Convey("clicking Start in the compact layout starts the timer", func() {
+				fm, _ = fm.Update(tea.WindowSizeMsg{Width: 50, Height: 12})
+				So(fm.(TimerView).layout, ShouldEqual, CompactLayout)
+				x, y := find(fm, "Start")
+				_, cmd := fm.Update(tea.MouseMsg{X: x, Y: y, Type: tea.MouseLeft})
+				So(cmd, ShouldNotBeNil)
+			})
+
+			Convey("clicking outside the buttons does nothing", func() {
+				_, cmd := fm.Update(tea.MouseMsg{X: 0, Y: 0, Type: tea.MouseLeft})
+				So(cmd, ShouldBeNil)
+			})
+
+			Convey("the wheel over the progress bar nudges the time left", func() {
+				x, y := find(fm, "25m0s")
+				next, _ := fm.Update(tea.MouseMsg{X: x, Y: y, Type: tea.MouseWheelUp})
+				So(next.(TimerView).timer.Timeout, ShouldEqual, 25*time.Minute)
+
+				x, y = find(fm, "░")
+				next, _ = fm.Update(tea.MouseMsg{X: x, Y: y, Type: tea.MouseWheelUp})
+				So(next.(TimerView).timer.Timeout, ShouldEqual, 26*time.Minute)
+
+				next, _ = next.Update(tea.MouseMsg{X: x, Y: y, Type: tea.MouseWheelDown})
+				next, _ = next.Update(tea.MouseMsg{X: x, Y: y, Type: tea.MouseWheelDown})
+				So(next.(TimerView).timer.Timeout, ShouldEqual, 24*time.Minute)
+				So(next.(TimerView).PercentComplete(), ShouldEqual, 0)
 			})
 		})
 
 		Convey("the timer is running", func() {
-			var fm tea.Model = NewFocusMode("1s", time.Millisecond, 120, 40)
+			var fm tea.Model = NewFocusMode(time.Second, time.Millisecond, 120, 40, ModeOptions{})
 			fmm := fm.(TimerView)
 			fmm.started = true
 			fm = fmm
@@ -179,11 +432,13 @@ func TestTimerView(t *testing.T) {
 				msg2 := cmd()
 				So(fmt.Sprintf("%T", msg2), ShouldResemble, fmt.Sprintf("%T", timer.StartStopMsg{}))
 
+				So(fm.(TimerView).interruptions, ShouldEqual, 1)
+
 				fm, cmd = fm.Update(msg2)
 				So(fm.(TimerView).timer.Running(), ShouldBeFalse)
-				So(fm.(TimerView).keymaps[0].Enabled(), ShouldBeTrue)
-				So(fm.(TimerView).keymaps[1].Enabled(), ShouldBeFalse)
-				So(fm.(TimerView).keymaps[2].Enabled(), ShouldBeTrue)
+				So(fm.(TimerView).keys.Start.Enabled(), ShouldBeTrue)
+				So(fm.(TimerView).keys.Pause.Enabled(), ShouldBeFalse)
+				So(fm.(TimerView).keys.Stop.Enabled(), ShouldBeTrue)
 			})
 
 			Convey("Pressing s stops, and resets, the timer", func() {
@@ -194,11 +449,11 @@ func TestTimerView(t *testing.T) {
 				}
 
 				fm, cmd := fm.Update(msg)
-				So(cmd, ShouldBeNil)
+				So(cmd(), ShouldHaveSameTypeAs, TimerVoidedMsg{})
 				So(fm.(TimerView).started, ShouldBeFalse)
-				So(fm.(TimerView).keymaps[0].Enabled(), ShouldBeTrue)
-				So(fm.(TimerView).keymaps[1].Enabled(), ShouldBeFalse)
-				So(fm.(TimerView).keymaps[2].Enabled(), ShouldBeFalse)
+				So(fm.(TimerView).keys.Start.Enabled(), ShouldBeTrue)
+				So(fm.(TimerView).keys.Pause.Enabled(), ShouldBeFalse)
+				So(fm.(TimerView).keys.Stop.Enabled(), ShouldBeFalse)
 			})
 
 			Convey("Pressing q exits the application", func() {
@@ -213,6 +468,23 @@ func TestTimerView(t *testing.T) {
 				So(fmt.Sprintf("%T", msg2), ShouldEqual, fmt.Sprintf("%T", tea.Quit()))
 			})
 
+			Convey("Timing out sends how the period went", func() {
+				_, cmd := fm.Update(timer.TimeoutMsg{ID: fmm.timer.ID()})
+				msg := cmd().(TimerCompleteMsg)
+				So(msg.Planned, ShouldEqual, time.Second)
+				So(msg.Elapsed, ShouldBeGreaterThan, 0)
+				So(msg.Skipped, ShouldBeFalse)
+			})
+
+			Convey("A timeout from another timer is ignored", func() {
+				other := NewFocusMode(time.Second, time.Millisecond, 120, 40, ModeOptions{})
+				_, cmd := fm.Update(timer.TimeoutMsg{ID: other.timer.ID()})
+				So(cmd, ShouldBeNil)
+
+				_, cmd = fm.Update(timer.TimeoutMsg{ID: fmm.timer.ID()})
+				So(cmd, ShouldNotBeNil)
+			})
+
 			Convey("Tick message increases percent complete", func() {
 				msg := timer.TickMsg{
 					ID:      fmm.timer.ID(),
@@ -224,8 +496,49 @@ func TestTimerView(t *testing.T) {
 				So(fm.(TimerView).progressBar.Percent(), ShouldAlmostEqual, 0.001)
 			})
 
+			Convey("Tick crossing a warning threshold sends the warning", func() {
+				sequences := []string{}
+				wm := NewFocusMode(time.Second, time.Millisecond, 120, 40, ModeOptions{
+					Notifier: notifications.Notifier{
+						Backend: notifications.Kitty,
+						Output:  func(s string) { sequences = append(sequences, s) },
+					},
+					Warnings: []Warning{{Before: 500 * time.Millisecond, Title: "Nearly", Body: "there"}},
+				})
+				wm.started = true
+				wm.timer.Timeout = 501 * time.Millisecond
+				msg := timer.TickMsg{ID: wm.timer.ID()}
+
+				next, cmd := wm.Update(msg)
+				So(sequences, ShouldBeEmpty)
+				runBatch(cmd)
+				So(len(sequences), ShouldEqual, 2)
+
+				_, cmd = next.Update(msg)
+				runBatch(cmd)
+				So(len(sequences), ShouldEqual, 2)
+			})
+
+			Convey("Tick reports the time left in the terminal title", func() {
+				sequences := []string{}
+				rm := NewFocusMode(time.Second, time.Millisecond, 120, 40, ModeOptions{
+					Reporter: terminal.Reporter{
+						Title:  true,
+						Output: func(s string) { sequences = append(sequences, s) },
+					},
+				})
+				rm.started = true
+				rm.timer.Timeout = 61 * time.Second
+
+				_, cmd := rm.Update(timer.TickMsg{ID: rm.timer.ID()})
+				So(sequences, ShouldBeEmpty)
+
+				runBatch(cmd)
+				So(sequences, ShouldResemble, []string{terminal.TitleSequence("🍅 01:01 Focus")})
+			})
+
 			Reset(func() {
-				fm = NewFocusMode("1s", time.Millisecond, 120, 40)
+				fm = NewFocusMode(time.Second, time.Millisecond, 120, 40, ModeOptions{})
 				fmm = fm.(TimerView)
 				fmm.started = true
 				fm = fmm
@@ -236,3 +549,19 @@ func TestTimerView(t *testing.T) {
 		})
 	})
 }
+
+// runBatch runs cmd, and every command in any batch it returns, discarding
+// their messages. Bubbletea doesn't export its batch message, so it is
+// recognised by its underlying type.
+func runBatch(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	msg := reflect.ValueOf(cmd())
+	batch := reflect.TypeOf([]tea.Cmd{})
+	if msg.IsValid() && msg.Type().ConvertibleTo(batch) {
+		for _, c := range msg.Convert(batch).Interface().([]tea.Cmd) {
+			runBatch(c)
+		}
+	}
+}